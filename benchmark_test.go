@@ -0,0 +1,145 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupDisabled(tb testing.TB) {
+	shutdown, err := tracing.Setup(tracing.WithDisabled())
+	require.NoError(tb, err)
+	tb.Cleanup(shutdown)
+}
+
+func Test_Disabled(t *testing.T) {
+	setupDisabled(t)
+	assert.True(t, tracing.Disabled())
+	assert.True(t, tracing.Noop())
+
+	ctx := context.Background()
+	ctx2, sp := tracing.StartSpan(ctx, "disabled", tracing.WithSpanKind(tracing.SpanKindServer))
+	assert.Equal(t, ctx, ctx2)
+	assert.False(t, sp.SpanContext().IsValid())
+	assert.False(t, tracing.SpanFromContext(ctx2).SpanContext().IsValid())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, sp := tracing.StartSpan(ctx, "disabled", tracing.WithSpanKind(tracing.SpanKindServer))
+		sp.SetStatus(tracing.OK, "")
+		sp.End()
+		_ = tracing.SpanFromContext(ctx)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkStartSpan_Disabled(b *testing.B) {
+	setupDisabled(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, sp := tracing.StartSpan(ctx, "disabled", tracing.WithSpanKind(tracing.SpanKindServer))
+		sp.End()
+	}
+}
+
+func BenchmarkSpanFromContext_Disabled(b *testing.B) {
+	setupDisabled(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tracing.SpanFromContext(ctx)
+	}
+}
+
+// setupSDK sets a sdk TracerProvider which samples all spans without exporter,
+// so that benchmarks only measure the overhead of tracing.
+func setupSDK(b *testing.B) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample())))
+	b.Cleanup(func() { otel.SetTracerProvider(previous) })
+}
+
+func Test_StartSpan_Allocs(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.Background()
+	tracer := provider.Tracer("raw")
+	raw := testing.AllocsPerRun(100, func() {
+		_, sp := tracer.Start(ctx, "raw", trace.WithSpanKind(trace.SpanKindServer))
+		sp.End()
+	})
+	wrapped := testing.AllocsPerRun(100, func() {
+		_, sp := tracing.StartSpan(ctx, "wrapped", tracing.WithSpanKind(tracing.SpanKindServer))
+		sp.End()
+	})
+
+	// StartSpan should only allocate the bridge stored in context more than otel.
+	assert.LessOrEqual(t, wrapped, raw+1)
+}
+
+func BenchmarkStartSpan(b *testing.B) {
+	setupSDK(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, sp := tracing.StartSpan(ctx, "root", tracing.WithSpanKind(tracing.SpanKindServer))
+		sp.End()
+	}
+}
+
+func BenchmarkStartSpan_Child(b *testing.B) {
+	setupSDK(b)
+	ctx, root := tracing.StartSpan(context.Background(), "root")
+	defer root.End()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, sp := tracing.StartSpan(ctx, "child")
+		sp.End()
+	}
+}
+
+func BenchmarkSpan_SetAttributes(b *testing.B) {
+	setupSDK(b)
+	_, sp := tracing.StartSpan(context.Background(), "root")
+	defer sp.End()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.SetAttributes(
+			attribute.String("key", "value"),
+			attribute.Int("int", i),
+		)
+	}
+}
+
+func BenchmarkSpanFromContext(b *testing.B) {
+	setupSDK(b)
+	ctx, sp := tracing.StartSpan(context.Background(), "root")
+	defer sp.End()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tracing.SpanFromContext(ctx)
+	}
+}
@@ -2,6 +2,8 @@ package tracing
 
 import (
 	"context"
+	"reflect"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -13,16 +15,33 @@ var (
 	tracerInstrumentationVersion = "v1.3.0"
 )
 
-var (
-	tracingSpanKey = struct{}{}
-)
+// tracerCache caches the tracer created from provider, it's replaced once the
+// global TracerProvider changes, such as: Setup is called again.
+type tracerCache struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+var _tracerCache atomic.Value
+
+// getTracer returns the tracer of the global TracerProvider, and avoids looking
+// up the tracer in provider for every span.
+func getTracer() trace.Tracer {
+	provider := otel.GetTracerProvider()
+	if c, ok := _tracerCache.Load().(*tracerCache); ok && c.provider == provider {
+		return c.tracer
+	}
 
-func contextWithWrapSpan(ctx context.Context, sp Span) context.Context {
-	if sp == nil {
-		return ctx
+	tracer := provider.Tracer(tracerInstrumentationLibName,
+		trace.WithInstrumentationVersion(tracerInstrumentationVersion),
+	)
+	// providers which are not comparable could not be cached, since comparing
+	// them panics.
+	if reflect.TypeOf(provider).Comparable() {
+		_tracerCache.Store(&tracerCache{provider: provider, tracer: tracer})
 	}
 
-	return context.WithValue(ctx, tracingSpanKey, sp)
+	return tracer
 }
 
 func spanFromContext(ctx context.Context) Span {
@@ -30,43 +49,66 @@ func spanFromContext(ctx context.Context) Span {
 		return noopSpan{}
 	}
 
-	if sp, ok := ctx.Value(tracingSpanKey).(Span); ok {
-		return sp
+	sp := trace.SpanFromContext(ctx)
+	if b, ok := sp.(*spanBridge); ok {
+		return spanAgent{root: b}
 	}
 
-	return noopSpan{}
+	// the span is started by other instrumentation, such as: otelhttp, or it's
+	// a remote span extracted by propagator.
+	if !sp.SpanContext().IsValid() {
+		return noopSpan{}
+	}
+	var psc trace.SpanContext
+	if ro, ok := sp.(sdktrace.ReadOnlySpan); ok {
+		psc = ro.Parent()
+	}
+
+	return spanAgent{root: newSpanBridge(sp, psc)}
 }
 
 // StartSpan is alias of otel.Tracer("tracerName").Start() to avoid importing otel library in you project code.
+// It returns ctx and a noop Span without any allocation if tracing is disabled or Setup is never called.
 func StartSpan(ctx context.Context, operation string, opts ...SpanStartOption) (context.Context, Span) {
-	o := defaultSpanStartOption()
+	if Noop() {
+		return ctx, noopSpan{}
+	}
+
+	o := acquireSpanStartOption()
 	for _, opt := range opts {
 		opt.apply(o)
 	}
 
-	traceOptions := o.translateToTraceOptions()
-
-	ctx2, sp := otel.
-		Tracer(tracerInstrumentationLibName,
-			trace.WithInstrumentationVersion(tracerInstrumentationVersion),
-		).
-		Start(ctx, operation, traceOptions...)
+	// the parent is the span in ctx unless a new root is required, so there is
+	// no need to read it from the created span.
+	parent := trace.SpanContextFromContext(ctx)
+	psc := parent
+	if o.newRoot {
+		psc = trace.SpanContext{}
+	}
 
-	var psc *trace.SpanContext
-	if ro, ok := sp.(sdktrace.ReadOnlySpan); ok && ro.Parent().IsValid() {
-		psc = new(trace.SpanContext)
-		*psc = ro.Parent()
+	// otel only counts children of its own span type, so the parent must be
+	// unwrapped from spanBridge before starting the child.
+	startCtx := ctx
+	if b, ok := trace.SpanFromContext(ctx).(*spanBridge); ok && !o.newRoot {
+		startCtx = trace.ContextWithSpan(ctx, b.Span)
 	}
 
-	spw := wrapSpan(sp, psc)
-	ctx3 := contextWithWrapSpan(ctx2, spw)
+	_, sp := getTracer().Start(startCtx, operation, o.translateToTraceOptions(parent)...)
+	releaseSpanStartOption(o)
 
-	return ctx3, spw
+	b := newSpanBridge(sp, psc)
+	return trace.ContextWithSpan(ctx, b), spanAgent{root: b}
 }
 
 // SpanFromContext is alias of otel.SpanFromContext() to avoid importing
-// otel library in you project code.
+// otel library in you project code. Spans started by other OpenTelemetry
+// instrumentation are wrapped as Span too.
 func SpanFromContext(ctx context.Context) Span {
+	if Disabled() {
+		return noopSpan{}
+	}
+
 	return spanFromContext(ctx)
 }
 
@@ -76,6 +118,8 @@ type TraceContext struct {
 	isRemote                      bool
 	sampled                       bool
 	isValid                       bool
+	traceFlags                    trace.TraceFlags
+	traceState                    string
 }
 
 func (tc *TraceContext) Sampled() bool {
@@ -102,6 +146,15 @@ func (tc *TraceContext) IsRemote() bool {
 	return tc.isRemote
 }
 
+// TraceState returns the W3C tracestate of the span, empty if there is none.
+func (tc *TraceContext) TraceState() string {
+	if tc == nil {
+		return ""
+	}
+
+	return tc.traceState
+}
+
 func SpanContextFromContext(ctx context.Context) *TraceContext {
 	return TraceContextFromContext(ctx)
 }
@@ -71,11 +71,13 @@ type testContextSuite struct {
 	shutdown func()
 }
 
-func (t testContextSuite) TearDownSuite() {
+func (t *testContextSuite) TearDownSuite() {
 	t.shutdown()
 }
 
 func (t *testContextSuite) SetupSuite() {
+	// no collector while testing, do not wait for exporting.
+	t.T().Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "100")
 	shutdown, err := tracing.SetupDefault()
 	if err != nil {
 		panic(err)
@@ -84,7 +86,7 @@ func (t *testContextSuite) SetupSuite() {
 	t.shutdown = shutdown
 }
 
-func (t testContextSuite) Test_Compare_spanContext() {
+func (t *testContextSuite) Test_Compare_spanContext() {
 	ctx, sp := tracing.StartSpan(context.Background(), "example")
 	defer sp.End()
 
@@ -97,7 +99,7 @@ func (t testContextSuite) Test_Compare_spanContext() {
 	t.Equal(sc2.IsRemote(), sc.IsRemote())
 }
 
-func (t testContextSuite) Test_TraceContextFromContext() {
+func (t *testContextSuite) Test_TraceContextFromContext() {
 	ctx, sp := tracing.StartSpan(context.Background(), "example")
 	defer sp.End()
 	tc1 := sp.SpanContext()
@@ -1,9 +1,12 @@
 package tracinggin
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+
 	tracing "github.com/yeqown/opentelemetry-quake"
 )
 
@@ -34,6 +37,9 @@ func (sa sentryAdapter) Keys() []string {
 }
 
 // SentryCarrierAdaptor is an adapter that let sentry trace is valid for open-telemetry.
+//
+// Deprecated: use tracing.WithPropagators(tracing.W3CPropagator(), tracing.SentryPropagator())
+// in Setup instead, which works for all contrib packages and outbound requests.
 func SentryCarrierAdaptor(h http.Header) tracing.TraceContextCarrier {
 	carrier := sentryAdapter{Header: h}
 
@@ -44,34 +50,28 @@ func SentryCarrierAdaptor(h http.Header) tracing.TraceContextCarrier {
 	return &carrier
 }
 
-// translateSentryToOpenTelemetry matches either
-//
-// 	TRACE_ID - SPAN_ID
-// 	[[:xdigit:]]{32}-[[:xdigit:]]{16}
-// or
-// 	TRACE_ID - SPAN_ID - SAMPLED
-// 	[[:xdigit:]]{32}-[[:xdigit:]]{16}-[01]
-//
-// var sentryTracePattern = regexp.MustCompile(`^([[:xdigit:]]{32})-([[:xdigit:]]{16})(?:-([01]))?$`)
-//
-// links:
-// - github.com/sentry/sentry-go/tracing.go#L229-259
+// translateSentryToOpenTelemetry translates sentry-trace into traceparent by
+// tracing.SentryPropagator, it returns "" if sentryTrace is invalid. A value
+// which is already a traceparent is kept.
 func translateSentryToOpenTelemetry(sentryTrace string) string {
 	if len(sentryTrace) == 0 {
 		return ""
 	}
-
-	arr := strings.Split(sentryTrace, "-")
-	if len(arr) != 3 {
-		return sentryTrace
+	if strings.Count(sentryTrace, "-") == 3 {
+		if _, err := tracing.ParseTraceContext(sentryTrace, ""); err == nil {
+			return sentryTrace
+		}
+		return ""
 	}
-	switch arr[2] {
-	case "1":
-		arr[2] = "01"
-	case "0":
-		arr[2] = "00"
+
+	carrier := tracing.NewMapCarrier()
+	carrier.Set("sentry-trace", sentryTrace)
+	sc := trace.SpanContextFromContext(tracing.SentryPropagator().Extract(context.Background(), carrier))
+	if !sc.IsValid() {
+		return ""
 	}
-	arr2 := append([]string{"00"}, arr...)
 
-	return strings.Join(arr2, "-")
+	traceparent := tracing.NewMapCarrier()
+	tracing.W3CPropagator().Inject(trace.ContextWithSpanContext(context.Background(), sc), traceparent)
+	return traceparent.Get("traceparent")
 }
@@ -0,0 +1,163 @@
+package tracinggin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+const (
+	// ginErrorTypeKey is the class of gin error: bind, render, public, private
+	// or other.
+	ginErrorTypeKey = attribute.Key("gin.error.type")
+	// ginErrorMetaKey is the metadata attached by gin.Error.SetMeta.
+	ginErrorMetaKey = attribute.Key("gin.error.meta")
+	// ginErrorsKey is the number of c.Errors.
+	ginErrorsKey = attribute.Key("gin.errors")
+	// ginErrorTypesKey are the distinct classes of c.Errors.
+	ginErrorTypesKey = attribute.Key("gin.error.types")
+
+	// ginErrorTypeOther is the class of gin.ErrorTypeAny and unknown types,
+	// they're treated as failures of server.
+	ginErrorTypeOther = "other"
+)
+
+// RecoveryHandler writes the response after a panic is recovered.
+type RecoveryHandler func(c *gin.Context, recovered interface{})
+
+// defaultRecoveryHandler responds 500 unless the response has been written.
+func defaultRecoveryHandler(c *gin.Context, _ interface{}) {
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+
+	c.AbortWithStatus(http.StatusInternalServerError)
+}
+
+type captureConfig struct {
+	recoveryHandler RecoveryHandler
+}
+
+type CaptureOption interface {
+	apply(*captureConfig)
+}
+
+type fnCaptureOption func(*captureConfig)
+
+func (f fnCaptureOption) apply(c *captureConfig) { f(c) }
+
+// WithRecoveryHandler sets the handler to respond a recovered panic if
+// repanic is false. Default: respond 500.
+func WithRecoveryHandler(handler RecoveryHandler) CaptureOption {
+	return fnCaptureOption(func(c *captureConfig) {
+		if handler != nil {
+			c.recoveryHandler = handler
+		}
+	})
+}
+
+// CaptureException captures error and panic to open-telemetry. Every error
+// in c.Errors is recorded with its class and metadata, only private and
+// render errors fail the span since bind and public errors are caused by
+// client. A panic is recorded and then re-panicked if repanic is true,
+// otherwise it's responded by the recovery handler.
+func CaptureException(repanic bool, opts ...CaptureOption) gin.HandlerFunc {
+	cfg := &captureConfig{
+		recoveryHandler: defaultRecoveryHandler,
+	}
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	return func(c *gin.Context) {
+		// get current span to record, if span is nil then return directly.
+		sp := tracing.SpanFromContext(extract(c))
+		if sp == nil {
+			c.Next()
+			return
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				recordErrors(sp, c.Errors)
+				// FIXED(@yeqown): record stack trace.
+				sp.RecordError(fmt.Errorf("panic %v", r), tracing.WithStackTrace())
+				sp.SetStatus(tracing.Error, fmt.Sprintf("panic %v", r))
+				if repanic {
+					panic(r)
+				}
+				cfg.recoveryHandler(c, r)
+			}
+		}()
+
+		c.Next()
+		recordErrors(sp, c.Errors)
+		setServerStatus(sp, c.Writer.Status())
+	}
+}
+
+// recordErrors records every error of errs as an exception event, and the
+// classes of them as span attributes.
+func recordErrors(sp tracing.Span, errs []*gin.Error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	types := make([]string, 0, 2)
+	seen := make(map[string]struct{}, 2)
+	var failed *gin.Error
+	for _, e := range errs {
+		typ := errorTypeName(e.Type)
+		attrs := []attribute.KeyValue{ginErrorTypeKey.String(typ)}
+		if e.Meta != nil {
+			attrs = append(attrs, ginErrorMetaKey.String(fmt.Sprintf("%v", e.Meta)))
+		}
+		sp.RecordError(e.Err, tracing.WithEventAttributes(attrs...))
+
+		if _, ok := seen[typ]; !ok {
+			seen[typ] = struct{}{}
+			types = append(types, typ)
+		}
+		if typ == "private" || typ == "render" || typ == ginErrorTypeOther {
+			failed = e
+		}
+	}
+
+	sp.SetAttributes(ginErrorsKey.Int(len(errs)), ginErrorTypesKey.StringSlice(types))
+	if failed != nil {
+		sp.SetStatus(tracing.Error, failed.Error())
+	}
+}
+
+// errorTypeName classifies t. Exact values are checked first, since
+// gin.ErrorTypeAny sets every bit. Then bind and render take precedence, since
+// they're combined with other types by gin. gin.ErrorTypeNu has the same value
+// as gin.ErrorTypePublic, so it's reported as "public".
+func errorTypeName(t gin.ErrorType) string {
+	switch t {
+	case gin.ErrorTypeAny:
+		return ginErrorTypeOther
+	case gin.ErrorTypePublic:
+		return "public"
+	case gin.ErrorTypePrivate:
+		return "private"
+	}
+
+	switch {
+	case t&gin.ErrorTypeBind != 0:
+		return "bind"
+	case t&gin.ErrorTypeRender != 0:
+		return "render"
+	case t&gin.ErrorTypePublic != 0:
+		return "public"
+	case t&gin.ErrorTypePrivate != 0:
+		return "private"
+	default:
+		return ginErrorTypeOther
+	}
+}
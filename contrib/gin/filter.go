@@ -0,0 +1,105 @@
+package tracinggin
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Skipper reports whether the request should not be traced.
+type Skipper func(c *gin.Context) bool
+
+// WithSkipper skips tracing requests which skipper returns true for, such
+// as: health checks. Skipped requests still carry the trace context of
+// caller, so TracingContextFrom works, but no span is created.
+func WithSkipper(skipper Skipper) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		if skipper != nil {
+			c.skippers = append(c.skippers, skipper)
+		}
+	})
+}
+
+// WithIncludePaths only traces requests whose path matches any of patterns.
+// patterns are the same as path.Match, such as: /api/*, /users/*/orders, so
+// "*" never crosses "/". A trailing "/**" matches the prefix and everything
+// under it, such as: /api/** matches /api, /api/users and /api/users/1.
+func WithIncludePaths(patterns ...string) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.includePaths = append(c.includePaths, validPatterns(patterns)...)
+	})
+}
+
+// WithExcludePaths skips tracing requests whose path matches any of
+// patterns, such as: /healthz, /metrics, /debug/**. patterns are the same as
+// WithIncludePaths. It takes precedence over WithIncludePaths.
+func WithExcludePaths(patterns ...string) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.excludePaths = append(c.excludePaths, validPatterns(patterns)...)
+	})
+}
+
+// validPatterns drops malformed patterns with a warning.
+func validPatterns(patterns []string) []string {
+	valid := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.TrimSuffix(pattern, anyDepthSuffix), ""); err != nil {
+			fmt.Printf("[med/opentelemetry] WARNNING: invalid path pattern %q: %v\n", pattern, err)
+			continue
+		}
+		valid = append(valid, pattern)
+	}
+
+	return valid
+}
+
+// anyDepthSuffix makes a pattern match its prefix and every path under it.
+const anyDepthSuffix = "/**"
+
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func match(pattern, p string) bool {
+	prefix, anyDepth := strings.CutSuffix(pattern, anyDepthSuffix)
+	if !anyDepth {
+		matched, _ := path.Match(pattern, p)
+		return matched
+	}
+
+	// try every leading part of p which ends at a segment boundary.
+	for i := 0; i <= len(p); i++ {
+		if i != len(p) && p[i] != '/' {
+			continue
+		}
+		if matched, _ := path.Match(prefix, p[:i]); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skip reports whether the request should not be traced.
+func (cfg *config) skip(c *gin.Context) bool {
+	for _, skipper := range cfg.skippers {
+		if skipper(c) {
+			return true
+		}
+	}
+
+	p := c.Request.URL.Path
+	if matchAny(cfg.excludePaths, p) {
+		return true
+	}
+
+	return len(cfg.includePaths) != 0 && !matchAny(cfg.includePaths, p)
+}
@@ -0,0 +1,132 @@
+package tracinggin
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultMaxPayloadSize is the max bytes of payload to record.
+	defaultMaxPayloadSize = 4096
+	// truncatedMarker is appended to payload which exceeds the max size.
+	truncatedMarker = "...(truncated)"
+)
+
+// defaultPayloadContentTypes are the textual content types to record,
+// multipart uploads and binary downloads are excluded.
+var defaultPayloadContentTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/x-www-form-urlencoded",
+	"text/*",
+}
+
+// WithRecordRequest records query and body of request.
+func WithRecordRequest() TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.logRequest = true
+	})
+}
+
+// WithRecordResponse records body of response.
+func WithRecordResponse() TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.logResponse = true
+	})
+}
+
+// WithMaxPayloadSize sets the max bytes of recorded payload, payload which
+// exceeds it is truncated with a marker. Default: 4096.
+func WithMaxPayloadSize(size int) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		if size > 0 {
+			c.maxPayloadSize = size
+		}
+	})
+}
+
+// WithPayloadContentTypes sets the content types of payload to record, a
+// type could end with /* to match all subtypes, such as: text/*. Default:
+// application/json, application/xml, application/x-www-form-urlencoded and
+// text/*.
+func WithPayloadContentTypes(contentTypes ...string) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		if len(contentTypes) != 0 {
+			c.payloadContentTypes = contentTypes
+		}
+	})
+}
+
+// recordable reports whether payload of contentType should be recorded.
+func (cfg *config) recordable(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range cfg.payloadContentTypes {
+		if strings.HasSuffix(allowed, "/*") {
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+			continue
+		}
+		if mediaType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readCloser combines the captured prefix and the rest of request body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureRequest reads at most maxPayloadSize bytes of request body, the
+// body is restored so that handlers still read it entirely.
+func (cfg *config) captureRequest(c *gin.Context) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, attribute.String("query", c.Request.URL.RawQuery))
+
+	body := c.Request.Body
+	if body == nil || body == http.NoBody || !cfg.recordable(c.ContentType()) {
+		return attrs
+	}
+
+	captured, err := ioutil.ReadAll(io.LimitReader(body, int64(cfg.maxPayloadSize)+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(captured), body), Closer: body}
+	if err != nil || len(captured) == 0 {
+		return attrs
+	}
+
+	return append(attrs, payloadAttributes(captured, cfg.maxPayloadSize)...)
+}
+
+// payloadAttributes returns raw and truncated of payload, raw is copied
+// from payload so that payload could be reused.
+func payloadAttributes(payload []byte, maxSize int) []attribute.KeyValue {
+	truncated := len(payload) > maxSize
+	if truncated {
+		payload = payload[:maxSize]
+	}
+
+	raw := string(payload)
+	if truncated {
+		raw += truncatedMarker
+	}
+
+	return []attribute.KeyValue{
+		attribute.String("raw", raw),
+		attribute.Bool("truncated", truncated),
+	}
+}
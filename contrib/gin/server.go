@@ -1,17 +1,13 @@
 package tracinggin
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"go.opentelemetry.io/otel/attribute"
 
 	tracing "github.com/yeqown/opentelemetry-quake"
-	"github.com/yeqown/opentelemetry-quake/pkg"
+	"github.com/yeqown/opentelemetry-quake/semconv"
 )
 
 // config helps user to control Tracing middleware about how to
@@ -21,6 +17,29 @@ import (
 type config struct {
 	carrierFactory          func(h http.Header) tracing.TraceContextCarrier
 	logRequest, logResponse bool
+	// maxPayloadSize and payloadContentTypes bound the recorded payloads.
+	maxPayloadSize      int
+	payloadContentTypes []string
+	// legacyAttributes means recording attributes used before semconv, such as:
+	// http.status.code, http.path.
+	legacyAttributes bool
+	// requestID bridges legacy correlation IDs into traces, it's nil if
+	// WithRequestID is not used.
+	requestID *tracing.RequestIDExtractor
+	// inboundPolicy decides whether to trust trace context of request, it's
+	// nil if WithInboundPolicy is not used, and trace context is always
+	// trusted.
+	inboundPolicy *tracing.InboundPolicy
+	// traceResponse are the options of tracing.InjectTraceResponse.
+	traceResponse []tracing.TraceResponseOption
+
+	// skippers, includePaths and excludePaths filter requests to trace.
+	skippers                   []Skipper
+	includePaths, excludePaths []string
+
+	spanNameFormatter SpanNameFormatter
+	// headerCapture is nil if WithHeaderCapture is not used.
+	headerCapture *tracing.HeaderCapture
 }
 
 func defaultConfig() *config {
@@ -28,11 +47,19 @@ func defaultConfig() *config {
 		carrierFactory: builtinCarrierFactory,
 		logRequest:     false,
 		logResponse:    false,
+
+		maxPayloadSize:      defaultMaxPayloadSize,
+		payloadContentTypes: defaultPayloadContentTypes,
+
+		legacyAttributes:  false,
+		spanNameFormatter: defaultSpanNameFormatter,
+		// keep x-tracing-id besides traceresponse for compatibility.
+		traceResponse: []tracing.TraceResponseOption{tracing.WithTraceIDHeader(tracing.LegacyTraceIDHeader)},
 	}
 }
 
 func builtinCarrierFactory(h http.Header) tracing.TraceContextCarrier {
-	return h
+	return tracing.HeaderCarrier(h)
 }
 
 type TracingOption interface {
@@ -55,6 +82,8 @@ func WithCarrierFactory(adaptor func(h http.Header) tracing.TraceContextCarrier)
 	})
 }
 
+// WithRecordPayloads records both request and response, see WithRecordRequest
+// and WithRecordResponse.
 func WithRecordPayloads() TracingOption {
 	return newFunctionalOption(func(c *config) {
 		c.logRequest = true
@@ -62,6 +91,52 @@ func WithRecordPayloads() TracingOption {
 	})
 }
 
+// WithLegacyAttributes records http.status.success, http.status.code and
+// http.path besides the semantic convention attributes, it helps to migrate
+// dashboards and alerts which depend on them.
+func WithLegacyAttributes() TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.legacyAttributes = true
+	})
+}
+
+// WithRequestID reads legacy correlation IDs, such as: X-Request-ID, from
+// request. It's recorded as an attribute if there is no trace context, and
+// echoed back in response.
+func WithRequestID(opts ...tracing.RequestIDOption) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.requestID = tracing.NewRequestIDExtractor(opts...)
+	})
+}
+
+// WithTraceResponse customizes the headers of response which carry trace
+// context, traceresponse and x-tracing-id are written by default. For
+// example, drop x-tracing-id by:
+//
+//	WithTraceResponse(tracing.WithTraceIDHeader(""))
+func WithTraceResponse(opts ...tracing.TraceResponseOption) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.traceResponse = append(c.traceResponse, opts...)
+	})
+}
+
+// WithHeaderCapture records the allowlist of request and response headers,
+// Authorization, Cookie and Set-Cookie are redacted by default.
+func WithHeaderCapture(opts ...tracing.HeaderCaptureOption) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.headerCapture = tracing.NewHeaderCapture(opts...)
+	})
+}
+
+// WithInboundPolicy sets the trust policy of incoming trace context, it's
+// used by edge services which accept requests from the internet. The peer is
+// checked by the remote address of connection rather than X-Forwarded-For.
+func WithInboundPolicy(opts ...tracing.InboundPolicyOption) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		c.inboundPolicy = tracing.NewInboundPolicy(opts...)
+	})
+}
+
 // Tracing creates a new otel.Tracer if never created and returns a gin.HandlerFunc.
 // You only need to specify a CarrierFactory if your frontend doesn't obey TraceContext
 // specification https://www.w3.org/TR/trace-context, otherwise you can leave it nil.
@@ -72,60 +147,93 @@ func Tracing(opts ...TracingOption) gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
+		if tracing.Noop() {
+			c.Next()
+			return
+		}
+
 		// try to extract remote trace from request header.
-		parentCtx := tracing.
-			GetPropagator().
-			Extract(c.Request.Context(), opt.carrierFactory(c.Request.Header))
+		parentCtx, spanOpts := opt.extractParent(c)
+		if opt.skip(c) {
+			// no span, but downstream still continues the trace of caller.
+			inject(c, parentCtx)
+			c.Next()
+			return
+		}
+
+		if opt.requestID != nil {
+			parentCtx = opt.requestID.Extract(parentCtx, tracing.HeaderCarrier(c.Request.Header))
+		}
 
-		ctx, sp := tracing.StartSpan(parentCtx, c.FullPath(),
-			tracing.WithSpanKind(tracing.SpanKindServer),
-		)
+		ctx, sp := tracing.StartSpan(parentCtx, opt.spanNameFormatter(c), spanOpts...)
 		defer sp.End()
+		if opt.requestID != nil {
+			sp.SetAttributes(opt.requestID.Attributes(ctx)...)
+			opt.requestID.Inject(ctx, tracing.HeaderCarrier(c.Writer.Header()))
+		}
 
 		// inject trace context to gin context
 		inject(c, ctx)
-
-		rbw := getResponseBodyWriter(c)
-		c.Writer = rbw
+		if opt.headerCapture != nil {
+			sp.SetAttributes(opt.headerCapture.RequestAttributes(c.Request.Header)...)
+		}
 
 		if opt.logRequest {
-			body, err := c.GetRawData()
-			if err == nil && len(body) != 0 {
-				c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-			}
-
 			// add start event and record request body.
-			sp.LogFields("request",
-				attribute.String("query", c.Request.URL.RawQuery),
-				attribute.String("raw", pkg.ToString(body)),
-			)
+			sp.LogFields("request", opt.captureRequest(c)...)
 		}
 
-		c.Header("x-tracing-id", sp.SpanContext().TraceID)
+		// response body is buffered only if it's recorded.
+		var rbw *respBodyWriter
+		if opt.logResponse {
+			rbw = getResponseBodyWriter(c, opt, sp)
+			c.Writer = rbw
+		}
+
+		tracing.InjectTraceResponse(ctx, tracing.HeaderCarrier(c.Writer.Header()), opt.traceResponse...)
 		c.Next()
 
 		// add end event and record response body.
-		if opt.logResponse {
-			sp.LogFields("response", attribute.String("raw", rbw.String()))
+		if rbw != nil {
+			sp.LogFields("response", rbw.attributes()...)
+			// 只是释放 respBodyWriter 中额外存储的内存空间，并不会影响底层的 ResponseWriter
+			rbw.releaseBuffer()
 		}
-		// 只是释放 respBodyWriter 中额外存储的内存空间，并不会影响底层的 ResponseWriter
-		rbw.releaseBuffer()
 
-		if c.Writer.Status() >= 400 {
-			sp.SetStatus(tracing.Error, http.StatusText(c.Writer.Status()))
-		} else {
-			sp.SetStatus(tracing.OK, "")
+		setServerStatus(sp, c.Writer.Status())
+		if opt.headerCapture != nil {
+			sp.SetAttributes(opt.headerCapture.ResponseAttributes(c.Writer.Header())...)
 		}
 
-		sp.SetAttributes(
-			attribute.Bool("http.status.success", c.Writer.Status() < 400),
-			attribute.Int64("http.status.code", int64(c.Writer.Status())),
-			attribute.String("http.method", c.Request.Method),
-			attribute.String("http.path", c.Request.URL.Path),
-		)
+		httpServer := semconv.HTTPServerFromRequest(c.Request)
+		httpServer.Route = c.FullPath()
+		// respect the trusted proxies of gin engine.
+		httpServer.ClientIP = c.ClientIP()
+		httpServer.StatusCode = c.Writer.Status()
+		httpServer.ResponseContentLength = int64(c.Writer.Size())
+		sp.SetAttributes(httpServer.Attributes()...)
+		if opt.legacyAttributes {
+			sp.SetAttributes(httpServer.LegacyAttributes()...)
+		}
 	}
 }
 
+// extractParent extracts trace context of caller from request, and returns
+// the options to start server span.
+func (cfg *config) extractParent(c *gin.Context) (context.Context, []tracing.SpanStartOption) {
+	carrier := cfg.carrierFactory(c.Request.Header)
+	spanOpts := []tracing.SpanStartOption{tracing.WithSpanKind(tracing.SpanKindServer)}
+	if cfg.inboundPolicy == nil {
+		return tracing.GetPropagator().Extract(c.Request.Context(), carrier), spanOpts
+	}
+
+	ctx, policyOpt := cfg.inboundPolicy.Extract(c.Request.Context(), tracing.InboundRequest{
+		RemoteAddr: c.Request.RemoteAddr,
+		Carrier:    carrier,
+	})
+	return ctx, append(spanOpts, policyOpt)
+}
+
 const (
 	OtelTraceContextKey = "opentelemetry.gin"
 )
@@ -137,6 +245,10 @@ func inject(c *gin.Context, ctx context.Context) {
 func extract(c *gin.Context) context.Context {
 	v, ok := c.Get(OtelTraceContextKey)
 	if !ok || v == nil {
+		// Tracing is disabled or not used, keep the request context.
+		if c.Request != nil {
+			return c.Request.Context()
+		}
 		return context.Background()
 	}
 
@@ -149,33 +261,11 @@ func TracingContextFrom(c *gin.Context) context.Context {
 	return extract(c)
 }
 
-// CaptureException captures error and panic to open-telemetry.
-func CaptureException(repanic bool) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// get current span to record, if span is nil then return directly.
-		sp := tracing.SpanFromContext(extract(c))
-		if sp == nil {
-			c.Next()
-			return
-		}
-
-		defer func() {
-			if r := recover(); r != nil {
-				// FIXED(@yeqown): record stack trace.
-				sp.RecordError(fmt.Errorf("panic %v", r), tracing.WithStackTrace())
-				if repanic {
-					panic(r)
-				}
-			}
-		}()
-
-		c.Next()
-
-		if c.Writer.Status() >= 400 {
-			sp.SetStatus(tracing.Error, "ERR")
-			sp.RecordError(fmt.Errorf("%v", c.Writer.Status()))
-		} else {
-			sp.SetStatus(tracing.OK, "OK")
-		}
+// setServerStatus sets status of server span by the semantic conventions,
+// only 5xx is an error since 4xx is caused by client. The status is left
+// unset otherwise, so that an error recorded by handler is kept.
+func setServerStatus(sp tracing.Span, statusCode int) {
+	if statusCode >= http.StatusInternalServerError {
+		sp.SetStatus(tracing.Error, http.StatusText(statusCode))
 	}
 }
@@ -1,22 +1,38 @@
 package tracinggin_test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
-	tracinggin2 "github.com/yeqown/opentelemetry-quake/contrib/gin"
+	tracing "github.com/yeqown/opentelemetry-quake"
+	tracinggin "github.com/yeqown/opentelemetry-quake/contrib/gin"
 )
 
 func ExampleTracing() {
 	r := gin.Default()
 
 	r.Use(
-		tracinggin2.Tracing(),
+		tracinggin.Tracing(),
 	)
 
 	// sentry trace header
-	r.Use(tracinggin2.Tracing(
-		tracinggin2.WithCarrierFactory(tracinggin2.SentryCarrierAdaptor),
-		tracinggin2.WithRecordPayloads(),
+	r.Use(tracinggin.Tracing(
+		tracinggin.WithCarrierFactory(tracinggin.SentryCarrierAdaptor),
+		tracinggin.WithRecordPayloads(),
 	))
 }
 
@@ -24,6 +40,522 @@ func ExampleCaptureException() {
 	r := gin.Default()
 
 	r.Use(
-		tracinggin2.CaptureException(false),
+		tracinggin.CaptureException(false),
 	)
 }
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func serve(r *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func attributesOf(sp sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range sp.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func Test_Tracing_Attributes(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(tracinggin.Tracing())
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusNotFound, "not found") })
+
+	serve(r, http.MethodGet, "/users/1?a=b")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := attributesOf(spans[0])
+	assert.Equal(t, "GET", attrs["http.method"].AsString())
+	assert.Equal(t, "/users/:id", attrs["http.route"].AsString())
+	assert.Equal(t, "/users/1?a=b", attrs["http.target"].AsString())
+	assert.Equal(t, int64(404), attrs["http.status_code"].AsInt64())
+	// legacy attributes are not recorded by default.
+	assert.NotContains(t, attrs, attribute.Key("http.status.code"))
+	assert.NotContains(t, attrs, attribute.Key("http.path"))
+}
+
+func Test_Tracing_LegacyAttributes(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithLegacyAttributes()))
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	serve(r, http.MethodGet, "/users/1")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := attributesOf(spans[0])
+	assert.Equal(t, int64(200), attrs["http.status_code"].AsInt64())
+	assert.Equal(t, int64(200), attrs["http.status.code"].AsInt64())
+	assert.Equal(t, true, attrs["http.status.success"].AsBool())
+	assert.Equal(t, "/users/1", attrs["http.path"].AsString())
+}
+
+func Test_Tracing_Disabled(t *testing.T) {
+	recorder := setupRecorder(t)
+	shutdown, err := tracing.Setup(tracing.WithDisabled())
+	require.NoError(t, err)
+	defer shutdown()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRecordPayloads()))
+	r.GET("/ping", func(c *gin.Context) {
+		assert.Equal(t, c.Request.Context(), tracinggin.TracingContextFrom(c))
+		c.String(http.StatusOK, "pong")
+	})
+
+	w := serve(r, http.MethodGet, "/ping")
+	assert.Equal(t, "pong", w.Body.String())
+	assert.Empty(t, w.Header().Get("x-tracing-id"))
+	assert.Empty(t, recorder.Ended())
+}
+
+func Test_Tracing_RequestID(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRequestID()))
+	r.GET("/ping", func(c *gin.Context) {
+		assert.Equal(t, "req-1", tracing.RequestIDFromContext(tracinggin.TracingContextFrom(c)))
+		c.String(http.StatusOK, "pong")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "req-1", attributesOf(spans[0])["request.id"].AsString())
+}
+
+func Test_Tracing_TraceResponse(t *testing.T) {
+	setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing())
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	w := serve(r, http.MethodGet, "/ping")
+	traceresponse := w.Header().Get("traceresponse")
+	require.Len(t, traceresponse, 55)
+	// legacy header is kept by default.
+	assert.Equal(t, traceresponse[3:35], w.Header().Get("x-tracing-id"))
+
+	r = gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithTraceResponse(tracing.WithTraceIDHeader(""))))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	w = serve(r, http.MethodGet, "/ping")
+	assert.NotEmpty(t, w.Header().Get("traceresponse"))
+	assert.Empty(t, w.Header().Get("x-tracing-id"))
+}
+
+func Test_Tracing_InboundPolicy(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithInboundPolicy(tracing.WithTrustedCIDRs("10.0.0.0/8"))))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	traceparent := "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01"
+	for _, remoteAddr := range []string{"10.0.0.1:5678", "192.0.2.1:1234"} {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("traceparent", traceparent)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	// trusted peer continues the incoming trace, others start new traces.
+	assert.Equal(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", spans[0].SpanContext().TraceID().String())
+	assert.NotEqual(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", spans[1].SpanContext().TraceID().String())
+	assert.False(t, spans[1].Parent().IsValid())
+}
+
+func Test_Tracing_Skipper(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(
+		tracinggin.WithSkipper(func(c *gin.Context) bool { return c.Request.Method == http.MethodOptions }),
+		tracinggin.WithIncludePaths("/api/*", "/healthz", "["),
+		tracinggin.WithExcludePaths("/healthz"),
+	))
+	handler := func(c *gin.Context) {
+		// downstream continues the trace of caller even if it's skipped.
+		sc := tracing.SpanFromContext(tracinggin.TracingContextFrom(c)).SpanContext()
+		assert.Equal(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", sc.TraceID)
+		c.String(http.StatusOK, "ok")
+	}
+	r.GET("/healthz", handler)
+	r.GET("/internal", handler)
+	r.GET("/api/users", handler)
+	r.OPTIONS("/api/users", handler)
+
+	for _, target := range []string{"/healthz", "/internal", "/api/users"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("traceparent", "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	req.Header.Set("traceparent", "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/users", spans[0].Name())
+}
+
+func Test_Tracing_ExcludeNestedPaths(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(
+		tracinggin.WithExcludePaths("/debug/**", "/static/*"),
+	))
+	handler := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	r.GET("/debug", handler)
+	r.GET("/debug/pprof/heap", handler)
+	r.GET("/debugger", handler)
+	r.GET("/static/app.js", handler)
+	r.GET("/static/js/app.js", handler)
+
+	for _, target := range []string{"/debug", "/debug/pprof/heap", "/debugger", "/static/app.js", "/static/js/app.js"} {
+		serve(r, http.MethodGet, target)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /debugger", spans[0].Name())
+	// "*" does not cross "/".
+	assert.Equal(t, "GET /static/js/app.js", spans[1].Name())
+}
+
+func Test_Tracing_SpanName(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing())
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	r.Handle("PURGE", "/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	serve(r, http.MethodGet, "/users/1")
+	serve(r, http.MethodGet, "/not/found/1")
+	serve(r, "PURGE", "/users/1")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET /users/:id", spans[0].Name())
+	assert.Equal(t, "GET unmatched", spans[1].Name())
+	assert.Equal(t, "HTTP /users/:id", spans[2].Name())
+
+	r = gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithSpanNameFormatter(func(c *gin.Context) string {
+		return c.FullPath()
+	})))
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	serve(r, http.MethodGet, "/users/1")
+	spans = recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "/users/:id", spans[3].Name())
+}
+
+func Test_Tracing_Status(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies([]string{"192.0.2.0/24"}))
+	r.Use(tracinggin.Tracing(), tracinggin.CaptureException(false))
+	r.POST("/users", func(c *gin.Context) { c.String(http.StatusBadRequest, "bad request") })
+	r.GET("/users", func(c *gin.Context) { c.String(http.StatusInternalServerError, "internal") })
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice"}`))
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	serve(r, http.MethodGet, "/users")
+	serve(r, http.MethodGet, "/panic")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// 4xx is not an error of server.
+	attrs := attributesOf(spans[0])
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "203.0.113.7", attrs["http.client_ip"].AsString())
+	assert.Equal(t, int64(16), attrs["http.request_content_length"].AsInt64())
+	assert.Equal(t, int64(len("bad request")), attrs["http.response_content_length"].AsInt64())
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func eventAttributes(sp sdktrace.ReadOnlySpan, name string) map[attribute.Key]attribute.Value {
+	for _, event := range sp.Events() {
+		if event.Name == name {
+			m := make(map[attribute.Key]attribute.Value)
+			for _, kv := range event.Attributes {
+				m[kv.Key] = kv.Value
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+func Test_Tracing_RecordPayloads(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRecordPayloads(), tracinggin.WithMaxPayloadSize(8)))
+	r.POST("/echo", func(c *gin.Context) {
+		// handler still reads the entire body.
+		body, err := c.GetRawData()
+		require.NoError(t, err)
+		c.Data(http.StatusOK, c.ContentType(), body)
+	})
+
+	body := `{"name":"alice"}`
+	for _, contentType := range []string{"application/json; charset=utf-8", "application/octet-stream"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo?a=b", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, body, w.Body.String())
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	request := eventAttributes(spans[0], "request")
+	assert.Equal(t, "a=b", request["query"].AsString())
+	assert.Equal(t, `{"name":...(truncated)`, request["raw"].AsString())
+	assert.True(t, request["truncated"].AsBool())
+	response := eventAttributes(spans[0], "response")
+	assert.Equal(t, `{"name":...(truncated)`, response["raw"].AsString())
+	assert.True(t, response["truncated"].AsBool())
+
+	// binary payloads are not recorded.
+	request = eventAttributes(spans[1], "request")
+	assert.Equal(t, "a=b", request["query"].AsString())
+	assert.NotContains(t, request, attribute.Key("raw"))
+	assert.NotContains(t, eventAttributes(spans[1], "response"), attribute.Key("raw"))
+}
+
+func Test_Tracing_RecordResponseOnly(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRecordResponse()))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	serve(r, http.MethodGet, "/ping")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Nil(t, eventAttributes(spans[0], "request"))
+	response := eventAttributes(spans[0], "response")
+	assert.Equal(t, "pong", response["raw"].AsString())
+	assert.False(t, response["truncated"].AsBool())
+}
+
+func Test_Tracing_HeaderCapture(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithHeaderCapture(
+		tracing.WithRequestHeaders("Cookie", "X-Tenant"),
+		tracing.WithResponseHeaders("Content-Type"),
+	)))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("X-Other", "ignored")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := attributesOf(spans[0])
+	assert.Equal(t, []string{"REDACTED"}, attrs["http.request.header.cookie"].AsStringSlice())
+	assert.Equal(t, []string{"acme"}, attrs["http.request.header.x-tenant"].AsStringSlice())
+	assert.NotContains(t, attrs, attribute.Key("http.request.header.x-other"))
+	assert.Equal(t, []string{"text/plain; charset=utf-8"}, attrs["http.response.header.content-type"].AsStringSlice())
+}
+
+func Test_Tracing_StreamingResponse(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRecordResponse()))
+	r.GET("/events", func(c *gin.Context) {
+		for i := 0; i < 3; i++ {
+			c.SSEvent("message", "hello")
+			c.Writer.Flush()
+		}
+	})
+	r.GET("/download", func(c *gin.Context) {
+		_, ok := c.Writer.(http.CloseNotifier)
+		assert.True(t, ok)
+		assert.Nil(t, c.Writer.Pusher())
+
+		c.Header("Content-Type", "text/plain")
+		_, _ = c.Writer.WriteString("chunk1")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("chunk2")
+	})
+
+	w := serve(r, http.MethodGet, "/events")
+	assert.True(t, w.Flushed)
+	serve(r, http.MethodGet, "/download")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	response := eventAttributes(spans[0], "response")
+	assert.True(t, response["streaming"].AsBool())
+	assert.Equal(t, int64(len(w.Body.String())), response["bytes_written"].AsInt64())
+	assert.NotContains(t, response, attribute.Key("raw"))
+	assert.NotNil(t, eventAttributes(spans[0], "first_byte"))
+
+	response = eventAttributes(spans[1], "response")
+	assert.True(t, response["streaming"].AsBool())
+	assert.Equal(t, int64(12), response["bytes_written"].AsInt64())
+	assert.Equal(t, int64(2), response["chunks"].AsInt64())
+}
+
+func Test_Tracing_Hijack(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(tracinggin.WithRecordResponse()))
+	r.GET("/ws", func(c *gin.Context) {
+		conn, rw, err := c.Writer.Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+		_ = rw.Flush()
+	})
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ws")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the span ends after the hijacked connection is closed.
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, time.Second, 10*time.Millisecond)
+	spans := recorder.Ended()
+	response := eventAttributes(spans[0], "response")
+	assert.True(t, response["streaming"].AsBool())
+	assert.Equal(t, int64(0), response["bytes_written"].AsInt64())
+}
+
+func Test_CaptureException_Errors(t *testing.T) {
+	recorder := setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(), tracinggin.CaptureException(false))
+	r.POST("/bind", func(c *gin.Context) {
+		_ = c.Error(errors.New("invalid name")).SetType(gin.ErrorTypeBind)
+		c.String(http.StatusBadRequest, "bad request")
+	})
+	r.GET("/private", func(c *gin.Context) {
+		_ = c.Error(errors.New("db timeout")).SetMeta("users")
+		c.String(http.StatusOK, "degraded")
+	})
+
+	r.GET("/any", func(c *gin.Context) {
+		_ = c.Error(errors.New("unknown")).SetType(gin.ErrorTypeAny)
+		_ = c.Error(errors.New("not found")).SetType(gin.ErrorTypePublic)
+		c.String(http.StatusOK, "ok")
+	})
+
+	serve(r, http.MethodPost, "/bind")
+	serve(r, http.MethodGet, "/private")
+	serve(r, http.MethodGet, "/any")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// bind errors are caused by client.
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, []string{"bind"}, attributesOf(spans[0])["gin.error.types"].AsStringSlice())
+	exception := eventAttributes(spans[0], "exception")
+	assert.Equal(t, "invalid name", exception["exception.message"].AsString())
+	assert.Equal(t, "bind", exception["gin.error.type"].AsString())
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, int64(1), attributesOf(spans[1])["gin.errors"].AsInt64())
+	exception = eventAttributes(spans[1], "exception")
+	assert.Equal(t, "private", exception["gin.error.type"].AsString())
+	assert.Equal(t, "users", exception["gin.error.meta"].AsString())
+
+	// ErrorTypeAny sets every bit, but it's not a bind error.
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, []string{"other", "public"}, attributesOf(spans[2])["gin.error.types"].AsStringSlice())
+}
+
+func Test_CaptureException_Recovery(t *testing.T) {
+	setupRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(tracinggin.Tracing(), tracinggin.CaptureException(false))
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	w := serve(r, http.MethodGet, "/panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	r = gin.New()
+	r.Use(tracinggin.Tracing(), tracinggin.CaptureException(false,
+		tracinggin.WithRecoveryHandler(func(c *gin.Context, recovered interface{}) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprint(recovered)})
+		}),
+	))
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	w = serve(r, http.MethodGet, "/panic")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"error":"boom"}`, w.Body.String())
+}
@@ -0,0 +1,51 @@
+package tracinggin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpanNameFormatter returns the name of server span for request.
+type SpanNameFormatter func(c *gin.Context) string
+
+// WithSpanNameFormatter sets the formatter of server span name. Default:
+// "{METHOD} {route}", such as: GET /users/:id. The name should be bounded,
+// raw URL paths explode the cardinality of backends.
+func WithSpanNameFormatter(formatter SpanNameFormatter) TracingOption {
+	return newFunctionalOption(func(c *config) {
+		if formatter != nil {
+			c.spanNameFormatter = formatter
+		}
+	})
+}
+
+// knownMethods are the methods of RFC 9110 and PATCH, other methods are
+// named as HTTP to keep span names bounded.
+var knownMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodPost:    {},
+	http.MethodPut:     {},
+	http.MethodPatch:   {},
+	http.MethodDelete:  {},
+	http.MethodConnect: {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
+// defaultSpanNameFormatter names span as "{METHOD} {route}", or
+// "{METHOD} unmatched" if no route matches the request, such as: 404.
+func defaultSpanNameFormatter(c *gin.Context) string {
+	method := c.Request.Method
+	if _, ok := knownMethods[method]; !ok {
+		method = "HTTP"
+	}
+
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	return method + " " + route
+}
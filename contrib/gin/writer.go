@@ -1,40 +1,151 @@
 package tracinggin
 
 import (
+	"bufio"
 	"bytes"
+	"net"
+	"net/http"
+	"strings"
 	"sync"
 
-	"github.com/yeqown/opentelemetry-quake/pkg"
-
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
 )
 
+// respBodyWriter captures at most limit bytes of response body whose content
+// type is recordable. It's installed only if response is recorded.
+//
+// Streaming responses, such as: server-sent events, flushed or hijacked
+// responses, are recorded by bytes written and chunks instead of content,
+// since they could last for the life of connection.
 type respBodyWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	sp    tracing.Span
+	body  *bytes.Buffer
+	limit int
+	// recordable is checked on the first write, since Content-Type should be
+	// set before writing body.
+	recordable func(contentType string) bool
+	checked    bool
+	capture    bool
+	truncated  bool
+
+	streaming bool
+	written   int64
+	chunks    int
 }
 
-func (w respBodyWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+// CloseNotify and Pusher are forwarded by embedding gin.ResponseWriter.
+var (
+	_ http.Flusher       = (*respBodyWriter)(nil)
+	_ http.Hijacker      = (*respBodyWriter)(nil)
+	_ http.CloseNotifier = (*respBodyWriter)(nil)
+)
+
+func (w *respBodyWriter) Write(b []byte) (int, error) {
+	if n := w.observe(len(b)); n > 0 {
+		w.body.Write(b[:n])
+	}
 	return w.ResponseWriter.Write(b)
 }
 
-func (w respBodyWriter) String() string {
-	return pkg.ToString(w.body.Bytes())
+func (w *respBodyWriter) WriteString(s string) (int, error) {
+	if n := w.observe(len(s)); n > 0 {
+		w.body.WriteString(s[:n])
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush marks the response as streaming.
+func (w *respBodyWriter) Flush() {
+	w.markStreaming()
+	w.ResponseWriter.Flush()
+}
+
+// Hijack marks the response as streaming, such as: WebSocket. Data written
+// to the hijacked connection is not recorded.
+func (w *respBodyWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.markStreaming()
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *respBodyWriter) markStreaming() {
+	w.streaming = true
+	w.capture = false
+}
+
+// observe records a write of size bytes, and returns how many bytes of it
+// should be captured.
+func (w *respBodyWriter) observe(size int) int {
+	if w.chunks == 0 {
+		w.sp.LogFields("first_byte")
+	}
+	w.written += int64(size)
+	w.chunks++
+
+	if !w.checked {
+		w.checked = true
+		contentType := w.Header().Get("Content-Type")
+		if strings.HasPrefix(contentType, "text/event-stream") {
+			w.markStreaming()
+		} else if !w.streaming {
+			w.capture = w.recordable(contentType)
+		}
+	}
+	if !w.capture || w.truncated {
+		return 0
+	}
+
+	if remaining := w.limit - w.body.Len(); size > remaining {
+		w.truncated = true
+		return remaining
+	}
+	return size
+}
+
+// attributes returns the attributes of captured body, raw is copied from the
+// buffer so that it's safe to release the buffer.
+func (w *respBodyWriter) attributes() []attribute.KeyValue {
+	if w.streaming {
+		return []attribute.KeyValue{
+			attribute.Bool("streaming", true),
+			attribute.Int64("bytes_written", w.written),
+			attribute.Int("chunks", w.chunks),
+		}
+	}
+	if !w.capture {
+		return nil
+	}
+
+	raw := w.body.String()
+	if w.truncated {
+		raw += truncatedMarker
+	}
+
+	return []attribute.KeyValue{
+		attribute.String("raw", raw),
+		attribute.Bool("truncated", w.truncated),
+	}
 }
 
-func (w respBodyWriter) releaseBuffer() {
+func (w *respBodyWriter) releaseBuffer() {
 	if w.body == nil {
 		return
 	}
 
 	releaseBuffer(w.body)
+	w.body = nil
 }
 
-func getResponseBodyWriter(c *gin.Context) *respBodyWriter {
+func getResponseBodyWriter(c *gin.Context, cfg *config, sp tracing.Span) *respBodyWriter {
 	rbw := &respBodyWriter{
 		ResponseWriter: c.Writer,
+		sp:             sp,
 		body:           getBuffer(),
+		limit:          cfg.maxPayloadSize,
+		recordable:     cfg.recordable,
 	}
 
 	return rbw
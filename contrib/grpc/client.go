@@ -5,10 +5,11 @@ import (
 	"fmt"
 
 	tracing "github.com/yeqown/opentelemetry-quake"
+	"github.com/yeqown/opentelemetry-quake/semconv"
 
-	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 // TracingClientInterceptor returns a grpc.UnaryClientInterceptor suitable
@@ -30,6 +31,10 @@ func TracingClientInterceptor(optFuncs ...Option) grpc.UnaryClientInterceptor {
 		invoker grpc.UnaryInvoker,
 		opts ...grpc.CallOption,
 	) (err error) {
+		if tracing.Noop() {
+			return invoker(ctx, method, req, resp, cc, opts...)
+		}
+
 		parent := tracing.SpanFromContext(ctx)
 		if !parent.SpanContext().IsValid() {
 			// has no parent span, just skip tracing
@@ -44,7 +49,7 @@ func TracingClientInterceptor(optFuncs ...Option) grpc.UnaryClientInterceptor {
 		ctxWithSpan, clientSpan := tracing.StartSpan(ctx, method,
 			tracing.WithSpanKind(tracing.SpanKindClient),
 		)
-		clientSpan.SetAttributes(attribute.Bool(conventions.AttributeRPCService, true))
+		clientSpan.SetAttributes(semconv.RPCFromFullMethod(semconv.RPCSystemGRPC, method).Attributes()...)
 		defer clientSpan.End()
 		ctx = injectSpanContext(ctxWithSpan)
 
@@ -55,6 +60,7 @@ func TracingClientInterceptor(optFuncs ...Option) grpc.UnaryClientInterceptor {
 		}
 
 		err = invoker(ctx, method, req, resp, cc, opts...)
+		clientSpan.SetAttributes(semconv.GRPCStatusCode(uint32(status.Code(err))))
 		if err == nil {
 			if traceOpts.logPayloads {
 				clientSpan.LogFields("response",
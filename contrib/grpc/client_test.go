@@ -1,8 +1,18 @@
 package tracinggrpc_test
 
 import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
+	tracing "github.com/yeqown/opentelemetry-quake"
 	tracinggrpc "github.com/yeqown/opentelemetry-quake/contrib/grpc"
 )
 
@@ -16,3 +26,58 @@ func ExampleTracingClientInterceptor() {
 
 	_, _ = conn, err
 }
+
+func Test_TracingClientInterceptor_OtelParent(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	// the parent span is started by other instrumentation, such as: otelhttp.
+	ctx, parent := otel.Tracer("third-party").Start(context.Background(), "parent")
+	defer parent.End()
+
+	interceptor := tracinggrpc.TracingClientInterceptor()
+	err := interceptor(ctx, "/helloworld.Greeter/SayHello", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			require.True(t, ok)
+			assert.NotEmpty(t, md.Get("traceparent"))
+			return nil
+		})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/helloworld.Greeter/SayHello", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func Test_TracingClientInterceptor_SentryPropagator(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previous)
+	tracing.SetPropagator(tracing.NewCompositePropagator(
+		tracing.W3CPropagator(),
+		tracing.SentryPropagator(tracing.WithSentryRelease("v1")),
+	))
+	defer tracing.SetPropagator(tracing.W3CPropagator())
+
+	ctx, parent := tracing.StartSpan(context.Background(), "parent")
+	defer parent.End()
+	ctx = metadata.AppendToOutgoingContext(ctx, "baggage", "userId=alice")
+
+	interceptor := tracinggrpc.TracingClientInterceptor()
+	err := interceptor(ctx, "/helloworld.Greeter/SayHello", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			assert.Len(t, md.Get("sentry-trace"), 1)
+			// injected values are appended, existing values are kept.
+			baggage := md.Get("baggage")
+			require.Len(t, baggage, 2)
+			assert.Equal(t, "userId=alice", baggage[0])
+			assert.Contains(t, baggage[1], "sentry-release=v1")
+			return nil
+		})
+	require.NoError(t, err)
+}
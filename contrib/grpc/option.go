@@ -1,5 +1,9 @@
 package tracinggrpc
 
+import (
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
 // Option instances may be used in OpenTracing(Server|Client)Interceptor
 // initialization.
 //
@@ -15,17 +19,56 @@ func LogPayloads() Option {
 	}
 }
 
+// WithRequestID returns an Option that tells the server interceptor to read
+// legacy correlation IDs, such as: x-request-id, from incoming metadata. It's
+// recorded as an attribute if there is no trace context, and echoed back in
+// header of response.
+func WithRequestID(opts ...tracing.RequestIDOption) Option {
+	return func(o *options) {
+		o.requestID = tracing.NewRequestIDExtractor(opts...)
+	}
+}
+
+// WithTraceResponse returns an Option that tells the server interceptor to
+// write traceresponse into trailer of response, opts customize the keys.
+func WithTraceResponse(opts ...tracing.TraceResponseOption) Option {
+	return func(o *options) {
+		o.traceResponse = true
+		o.traceResponseOptions = opts
+	}
+}
+
+// WithInboundPolicy returns an Option that sets the trust policy of incoming
+// trace context for the server interceptor, the peer is checked by the
+// remote address of connection.
+func WithInboundPolicy(opts ...tracing.InboundPolicyOption) Option {
+	return func(o *options) {
+		o.inboundPolicy = tracing.NewInboundPolicy(opts...)
+	}
+}
+
 // The internal-only options struct. Obviously overkill at the moment; but will
 // scale well as production use dictates other configuration and tuning
 // parameters.
 type options struct {
 	logPayloads bool
+	requestID   *tracing.RequestIDExtractor
+	// inboundPolicy is nil if trace context is always trusted.
+	inboundPolicy *tracing.InboundPolicy
+
+	traceResponse        bool
+	traceResponseOptions []tracing.TraceResponseOption
 }
 
 // newOptions returns the default options.
 func newOptions() *options {
 	return &options{
 		logPayloads: false,
+		requestID:   nil,
+
+		inboundPolicy: nil,
+
+		traceResponse: false,
 	}
 }
 
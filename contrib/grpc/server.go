@@ -5,10 +5,11 @@ import (
 	"fmt"
 
 	tracing "github.com/yeqown/opentelemetry-quake"
+	"github.com/yeqown/opentelemetry-quake/semconv"
 
-	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 // TracingServerInterceptor returns a grpc.UnaryServerInterceptor suitable
@@ -30,12 +31,33 @@ func TracingServerInterceptor(optFuncs ...Option) grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (resp interface{}, err error) {
+		if tracing.Noop() {
+			return handler(ctx, req)
+		}
+
 		// try to extract TraceContext from ctx
-		ctxWithSpan, serverSpan := tracing.StartSpan(extractSpanContext(ctx), info.FullMethod,
-			tracing.WithSpanKind(tracing.SpanKindServer),
+		var (
+			parentCtx context.Context
+			spanOpts  = []tracing.SpanStartOption{tracing.WithSpanKind(tracing.SpanKindServer)}
 		)
-		serverSpan.SetAttributes(attribute.Bool(conventions.AttributeRPCSystem, true))
+		if opts.inboundPolicy != nil {
+			var policyOpt tracing.SpanStartOption
+			parentCtx, policyOpt = extractSpanContextWithPolicy(ctx, opts.inboundPolicy)
+			spanOpts = append(spanOpts, policyOpt)
+		} else {
+			parentCtx = extractSpanContext(ctx)
+		}
+		if opts.requestID != nil {
+			parentCtx = extractRequestID(parentCtx, opts.requestID)
+		}
+
+		ctxWithSpan, serverSpan := tracing.StartSpan(parentCtx, info.FullMethod, spanOpts...)
+		serverSpan.SetAttributes(semconv.RPCFromFullMethod(semconv.RPCSystemGRPC, info.FullMethod).Attributes()...)
 		defer serverSpan.End()
+		if opts.requestID != nil {
+			serverSpan.SetAttributes(opts.requestID.Attributes(ctxWithSpan)...)
+			injectRequestID(ctxWithSpan, opts.requestID)
+		}
 
 		if opts.logPayloads {
 			serverSpan.LogFields("request",
@@ -44,6 +66,10 @@ func TracingServerInterceptor(optFuncs ...Option) grpc.UnaryServerInterceptor {
 		}
 
 		resp, err = handler(ctxWithSpan, req)
+		if opts.traceResponse {
+			injectTraceResponse(ctxWithSpan, opts.traceResponseOptions)
+		}
+		serverSpan.SetAttributes(semconv.GRPCStatusCode(uint32(status.Code(err))))
 		if err == nil {
 			serverSpan.LogFields("response",
 				attribute.String("raw", marshalPbMessage(resp)),
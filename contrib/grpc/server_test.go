@@ -1,8 +1,24 @@
 package tracinggrpc_test
 
 import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
+	tracing "github.com/yeqown/opentelemetry-quake"
 	tracinggrpc "github.com/yeqown/opentelemetry-quake/contrib/grpc"
 )
 
@@ -15,3 +31,146 @@ func ExampleTracingServerInterceptor() {
 
 	_ = s
 }
+
+func Test_TracingServerInterceptor_Attributes(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	interceptor := tracinggrpc.TracingServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/helloworld.Greeter/SayHello"}
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "grpc", attrs["rpc.system"].AsString())
+	assert.Equal(t, "helloworld.Greeter", attrs["rpc.service"].AsString())
+	assert.Equal(t, "SayHello", attrs["rpc.method"].AsString())
+	assert.Equal(t, int64(codes.NotFound), attrs["rpc.grpc.status_code"].AsInt64())
+}
+
+// keysPropagator records keys of carrier into context.
+type keysPropagator struct{}
+
+type keysKey struct{}
+
+func (keysPropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+func (keysPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return context.WithValue(ctx, keysKey{}, carrier.Keys())
+}
+
+func (keysPropagator) Fields() []string { return nil }
+
+func Test_TracingServerInterceptor_CarrierKeys(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previousProvider)
+
+	previous := tracing.GetPropagator()
+	tracing.SetPropagator(tracing.FromTextMapPropagator(keysPropagator{}))
+	defer tracing.SetPropagator(previous)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uberctx-user", "alice"))
+	interceptor := tracinggrpc.TracingServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/helloworld.Greeter/SayHello"}
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, []string{"uberctx-user"}, ctx.Value(keysKey{}))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+func Test_TracingServerInterceptor_RequestID(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	interceptor := tracinggrpc.TracingServerInterceptor(tracinggrpc.WithRequestID())
+	info := &grpc.UnaryServerInfo{FullMethod: "/helloworld.Greeter/SayHello"}
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, "req-1", tracing.RequestIDFromContext(ctx))
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "request.id" {
+			assert.Equal(t, "req-1", kv.Value.AsString())
+			return
+		}
+	}
+	t.Fatal("request.id is not recorded")
+}
+
+// serverTransportStream records trailer set by interceptor.
+type serverTransportStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *serverTransportStream) SetHeader(md metadata.MD) error { return nil }
+func (s *serverTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func Test_TracingServerInterceptor_TraceResponse(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previous)
+
+	stream := &serverTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	interceptor := tracinggrpc.TracingServerInterceptor(tracinggrpc.WithTraceResponse())
+	info := &grpc.UnaryServerInfo{FullMethod: "/helloworld.Greeter/SayHello"}
+
+	var traceID string
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		traceID = tracing.SpanFromContext(ctx).SpanContext().TraceID
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	values := stream.trailer.Get("traceresponse")
+	require.Len(t, values, 1)
+	assert.Equal(t, traceID, values[0][3:35])
+}
+
+func Test_TracingServerInterceptor_InboundPolicy(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("traceparent", "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+	interceptor := tracinggrpc.TracingServerInterceptor(tracinggrpc.WithInboundPolicy(
+		tracing.WithTrustedCIDRs("10.0.0.0/8"),
+		tracing.WithUntrustedDecision(tracing.InboundIgnoreAndLink),
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/helloworld.Greeter/SayHello"}
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.NotEqual(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", spans[0].SpanContext().TraceID().String())
+	require.Len(t, spans[0].Links(), 1)
+	assert.Equal(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", spans[0].Links()[0].SpanContext.TraceID().String())
+}
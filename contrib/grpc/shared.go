@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/runtime/protoiface"
@@ -16,7 +18,7 @@ import (
 )
 
 var (
-	_ tracing.TraceContextCarrier = (*metadataCarrier)(nil)
+	_ tracing.TraceContextKeysCarrier = (*metadataCarrier)(nil)
 	// 将 pb.Message 处理为 JSON string 而不是使用默认的编码
 	jsonMarshallar = protojson.MarshalOptions{
 		EmitUnpopulated: true, // 打印零值
@@ -49,6 +51,15 @@ func (w metadataCarrier) Set(key, val string) {
 	w.MD[key] = append(w.MD[key], val)
 }
 
+func (w metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(w.MD))
+	for k := range w.MD {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
 func extractSpanContext(ctx context.Context) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -58,6 +69,23 @@ func extractSpanContext(ctx context.Context) context.Context {
 	return tracing.GetPropagator().Extract(ctx, metadataCarrier{MD: md})
 }
 
+func extractSpanContextWithPolicy(ctx context.Context, policy *tracing.InboundPolicy) (context.Context, tracing.SpanStartOption) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	}
+
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+
+	return policy.Extract(ctx, tracing.InboundRequest{
+		RemoteAddr: remoteAddr,
+		Carrier:    metadataCarrier{MD: md},
+	})
+}
+
 func injectSpanContext(ctx context.Context) context.Context {
 	md, ok := metadata.FromOutgoingContext(ctx)
 	if !ok {
@@ -70,6 +98,35 @@ func injectSpanContext(ctx context.Context) context.Context {
 	return metadata.NewOutgoingContext(ctx, md)
 }
 
+func extractRequestID(ctx context.Context, extractor *tracing.RequestIDExtractor) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return extractor.Extract(ctx, metadataCarrier{MD: md})
+}
+
+// injectRequestID echoes back the request ID in header of response, it's
+// ignored if ctx doesn't belong to a grpc server stream.
+func injectRequestID(ctx context.Context, extractor *tracing.RequestIDExtractor) {
+	md := metadata.New(nil)
+	extractor.Inject(ctx, metadataCarrier{MD: md})
+	if len(md) != 0 {
+		_ = grpc.SetHeader(ctx, md)
+	}
+}
+
+// injectTraceResponse writes traceresponse into trailer of response, it's
+// ignored if ctx doesn't belong to a grpc server stream.
+func injectTraceResponse(ctx context.Context, opts []tracing.TraceResponseOption) {
+	md := metadata.New(nil)
+	tracing.InjectTraceResponse(ctx, metadataCarrier{MD: md}, opts...)
+	if len(md) != 0 {
+		_ = grpc.SetTrailer(ctx, md)
+	}
+}
+
 func marshalPbMessage(v interface{}) string {
 	switch v.(type) {
 	case protoiface.MessageV1:
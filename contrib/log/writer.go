@@ -0,0 +1,87 @@
+package tracinglog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strconv"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+var _ io.Writer = (*writer)(nil)
+
+// writer appends trace fields of ctx to the end of each line written to w.
+type writer struct {
+	w      io.Writer
+	suffix []byte
+}
+
+// NewWriter returns an io.Writer which appends trace_id, span_id and sampled
+// of the span in ctx to each log line written by log.Logger, such as:
+//
+//	2021/12/01 10:00:00 hello trace_id=4bf9... span_id=00f0... sampled=true
+//
+// w would be returned directly if there is no valid span in ctx.
+func NewWriter(ctx context.Context, w io.Writer, opts ...tracing.LogKeysOption) io.Writer {
+	suffix := suffixFromContext(ctx, tracing.DefaultLogKeys().With(opts...))
+	if len(suffix) == 0 {
+		return w
+	}
+
+	return &writer{w: w, suffix: suffix}
+}
+
+// New returns a copy of l whose output is wrapped by NewWriter, the prefix
+// and flags of l are kept.
+func New(ctx context.Context, l *log.Logger, opts ...tracing.LogKeysOption) *log.Logger {
+	return log.New(NewWriter(ctx, l.Writer(), opts...), l.Prefix(), l.Flags())
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n := len(p)
+	line := bytes.TrimSuffix(p, []byte{'\n'})
+
+	buf := make([]byte, 0, len(p)+len(w.suffix)+1)
+	buf = append(buf, line...)
+	buf = append(buf, w.suffix...)
+	buf = append(buf, '\n')
+
+	if _, err := w.w.Write(buf); err != nil {
+		return 0, err
+	}
+
+	// report the length of p, since log.Logger does not care about the
+	// number of bytes written except for errors.
+	return n, nil
+}
+
+func suffixFromContext(ctx context.Context, keys tracing.LogKeys) []byte {
+	tc := tracing.TraceContextFromContext(ctx)
+	if !tc.IsValid() {
+		return nil
+	}
+
+	suffix := make([]byte, 0, 80)
+	if keys.TraceID != "" {
+		suffix = append(suffix, ' ')
+		suffix = append(suffix, keys.TraceID...)
+		suffix = append(suffix, '=')
+		suffix = append(suffix, tc.TraceID...)
+	}
+	if keys.SpanID != "" {
+		suffix = append(suffix, ' ')
+		suffix = append(suffix, keys.SpanID...)
+		suffix = append(suffix, '=')
+		suffix = append(suffix, tc.SpanID...)
+	}
+	if keys.Sampled != "" {
+		suffix = append(suffix, ' ')
+		suffix = append(suffix, keys.Sampled...)
+		suffix = append(suffix, '=')
+		suffix = strconv.AppendBool(suffix, tc.Sampled())
+	}
+
+	return suffix
+}
@@ -0,0 +1,58 @@
+package tracinglog_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+	tracinglog "github.com/yeqown/opentelemetry-quake/contrib/log"
+)
+
+func ExampleNew() {
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger := tracinglog.New(ctx, log.Default())
+	logger.Println("hello")
+}
+
+func ExampleNewWriter() {
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger := log.New(tracinglog.NewWriter(ctx, os.Stderr), "", log.LstdFlags)
+	logger.Println("hello")
+}
+
+func Test_NewWriter(t *testing.T) {
+	tc, err := tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	require.NoError(t, err)
+	ctx := tracing.ContextWithTraceContext(context.Background(), tc)
+
+	buf := bytes.NewBuffer(nil)
+	logger := log.New(tracinglog.NewWriter(ctx, buf, tracing.WithTraceIDKey("traceId")), "[app] ", 0)
+	logger.Println("hello")
+	logger.Print("world")
+	assert.Equal(t,
+		"[app] hello traceId=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 sampled=true\n"+
+			"[app] world traceId=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 sampled=true\n",
+		buf.String())
+
+	buf.Reset()
+	// the same keys could be shared by every log library of a service.
+	keys := []tracing.LogKeysOption{tracing.WithSpanIDKey(""), tracing.WithSampledKey("")}
+	logger1 := log.New(tracinglog.NewWriter(ctx, buf, keys...), "", 0)
+	logger1.Println("shared keys")
+	assert.Equal(t, "shared keys trace_id=4bf92f3577b34da6a3ce929d0e0e4736\n", buf.String())
+
+	buf.Reset()
+	logger2 := tracinglog.New(context.Background(), log.New(buf, "[app] ", 0))
+	logger2.Println("no span")
+	assert.Equal(t, "[app] no span\n", buf.String())
+}
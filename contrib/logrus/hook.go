@@ -0,0 +1,56 @@
+package tracinglogrus
+
+import (
+	"github.com/sirupsen/logrus"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// Hook is a logrus.Hook which stamps trace_id, span_id and sampled onto
+// log entries which carry a context.Context, such as:
+//
+//	logger.AddHook(tracinglogrus.NewHook())
+//	logger.WithContext(ctx).Info("hello")
+type Hook struct {
+	keys   tracing.LogKeys
+	levels []logrus.Level
+}
+
+// NewHook creates a Hook which fires on all levels.
+func NewHook(opts ...tracing.LogKeysOption) *Hook {
+	return &Hook{
+		keys:   tracing.DefaultLogKeys().With(opts...),
+		levels: logrus.AllLevels,
+	}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	tc := tracing.TraceContextFromContext(entry.Context)
+	if !tc.IsValid() {
+		return nil
+	}
+
+	// entry is duplicated by logrus before firing hooks, so it's safe to
+	// modify entry.Data directly.
+	if h.keys.TraceID != "" {
+		entry.Data[h.keys.TraceID] = tc.TraceID
+	}
+	if h.keys.SpanID != "" {
+		entry.Data[h.keys.SpanID] = tc.SpanID
+	}
+	if h.keys.Sampled != "" {
+		entry.Data[h.keys.Sampled] = tc.Sampled()
+	}
+
+	return nil
+}
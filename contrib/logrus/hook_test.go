@@ -0,0 +1,49 @@
+package tracinglogrus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+	tracinglogrus "github.com/yeqown/opentelemetry-quake/contrib/logrus"
+)
+
+func ExampleNewHook() {
+	logger := logrus.New()
+	logger.AddHook(tracinglogrus.NewHook())
+
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger.WithContext(ctx).Info("hello")
+}
+
+func Test_Hook(t *testing.T) {
+	tc, err := tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	require.NoError(t, err)
+	ctx := tracing.ContextWithTraceContext(context.Background(), tc)
+
+	logger, hook := test.NewNullLogger()
+	logger.AddHook(tracinglogrus.NewHook(tracing.WithSampledKey("")))
+
+	base := logger.WithField("k", "v")
+	base.WithContext(ctx).Info("hello")
+	assert.Equal(t, logrus.Fields{
+		"k":        "v",
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	}, hook.LastEntry().Data)
+	// the fields of base entry should not be polluted.
+	assert.Equal(t, logrus.Fields{"k": "v"}, base.Data)
+
+	logger.WithContext(context.Background()).Info("no span")
+	assert.Empty(t, hook.LastEntry().Data)
+
+	logger.Info("no context")
+	assert.Empty(t, hook.LastEntry().Data)
+}
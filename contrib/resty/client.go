@@ -1,17 +1,24 @@
 package tracingresty
 
 import (
+	"net/http"
+
 	tracing "github.com/yeqown/opentelemetry-quake"
 	"github.com/yeqown/opentelemetry-quake/pkg"
+	"github.com/yeqown/opentelemetry-quake/semconv"
 
 	"github.com/go-resty/resty/v2"
 	"go.opentelemetry.io/otel/attribute"
 )
 
 // Tracing middleware for resty client.
-func genPreRequestMiddleware() resty.RequestMiddleware {
+func genPreRequestMiddleware(cfg *config) resty.RequestMiddleware {
 
 	return func(client *resty.Client, request *resty.Request) error {
+		if tracing.Noop() {
+			return nil
+		}
+
 		// 1. start a new span from request context.
 		// 2. inject trace info into request header
 		ctx := request.Context()
@@ -20,12 +27,15 @@ func genPreRequestMiddleware() resty.RequestMiddleware {
 		)
 
 		request.SetContext(ctx)
-		tracing.GetPropagator().Inject(ctx, request.Header)
+		tracing.GetPropagator().Inject(ctx, tracing.HeaderCarrier(request.Header))
+
+		httpClient := semconv.NewHTTPClient(request.Method, request.URL)
+		sp.SetAttributes(httpClient.Attributes()...)
 
-		attrs := []attribute.KeyValue{
-			//attribute.String("raw",), TODO: get request data from request.Body
-			attribute.String("method", request.Method),
-			attribute.String("url", request.URL),
+		//attribute.String("raw",), TODO: get request data from request.Body
+		attrs := make([]attribute.KeyValue, 0, 4)
+		if cfg.legacyAttributes {
+			attrs = append(attrs, httpClient.LegacyAttributes()...)
 		}
 		if request.QueryParam != nil {
 			attrs = append(attrs, attribute.String("query", request.QueryParam.Encode()))
@@ -40,14 +50,27 @@ func genPreRequestMiddleware() resty.RequestMiddleware {
 	}
 }
 
-func genPostRequestMiddleware() resty.ResponseMiddleware {
+func genPostRequestMiddleware(cfg *config) resty.ResponseMiddleware {
 	return func(client *resty.Client, response *resty.Response) error {
+		if tracing.Noop() {
+			return nil
+		}
+
 		// 1. extract span from context
 		// 2. finish span and record response
 		ctx := response.Request.Context()
 		sp := tracing.SpanFromContext(ctx)
 		defer sp.End()
 
+		httpClient := httpClientOf(response.Request)
+		httpClient.StatusCode = response.StatusCode()
+		httpClient.ResponseContentLength = response.Size()
+		sp.SetAttributes(httpClient.Attributes()...)
+		if cfg.headerCapture != nil {
+			sp.SetAttributes(cfg.headerCapture.RequestAttributes(requestHeaderOf(response.Request))...)
+			sp.SetAttributes(cfg.headerCapture.ResponseAttributes(response.Header())...)
+		}
+
 		sp.LogFields("response",
 			attribute.String("raw", pkg.ToString(response.Body())),
 			attribute.String("status", response.Status()),
@@ -62,29 +85,62 @@ func genPostRequestMiddleware() resty.ResponseMiddleware {
 	}
 }
 
-func genTracingErrorHook() resty.ErrorHook {
+func genTracingErrorHook(cfg *config) resty.ErrorHook {
 	return func(request *resty.Request, err error) {
+		if tracing.Noop() {
+			return
+		}
+
 		ctx := request.Context()
 		sp := tracing.SpanFromContext(ctx)
 		defer sp.End()
 
+		sp.SetAttributes(httpClientOf(request).Attributes()...)
+		if cfg.headerCapture != nil {
+			sp.SetAttributes(cfg.headerCapture.RequestAttributes(requestHeaderOf(request))...)
+		}
 		sp.RecordError(err)
 	}
 }
 
+// httpClientOf creates semconv.HTTPClient from the raw request which has the
+// full URL resolved with the host URL of client, otherwise request.URL is used.
+func httpClientOf(request *resty.Request) semconv.HTTPClient {
+	if request.RawRequest != nil && request.RawRequest.URL != nil {
+		return semconv.NewHTTPClient(request.Method, request.RawRequest.URL.String())
+	}
+
+	return semconv.NewHTTPClient(request.Method, request.URL)
+}
+
+// requestHeaderOf returns the header of raw request which has the headers of
+// client merged, otherwise request.Header is used.
+func requestHeaderOf(request *resty.Request) http.Header {
+	if request.RawRequest != nil {
+		return request.RawRequest.Header
+	}
+
+	return request.Header
+}
+
 var (
 	_singletonKeeper = map[*resty.Client]struct{}{}
 )
 
 // InjectTracing injects, should keep singleton in one resty.Client.
-func InjectTracing(c *resty.Client) {
+func InjectTracing(c *resty.Client, opts ...Option) {
 	if _, ok := _singletonKeeper[c]; ok {
 		return
 	}
 
-	c.OnBeforeRequest(genPreRequestMiddleware())
-	c.OnAfterResponse(genPostRequestMiddleware())
-	c.OnError(genTracingErrorHook())
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+
+	c.OnBeforeRequest(genPreRequestMiddleware(cfg))
+	c.OnAfterResponse(genPostRequestMiddleware(cfg))
+	c.OnError(genTracingErrorHook(cfg))
 
 	_singletonKeeper[c] = struct{}{}
 }
@@ -2,8 +2,19 @@ package tracingresty_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
 	otelresty "github.com/yeqown/opentelemetry-quake/contrib/resty"
 )
 
@@ -23,3 +34,72 @@ func ExampleInjectTracing() {
 	// handle response and error
 	_, _ = resp, err
 }
+
+func Test_InjectTracing_Attributes(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	defer srv.Close()
+
+	client := resty.New().SetHostURL(srv.URL)
+	otelresty.InjectTracing(client, otelresty.WithLegacyAttributes())
+
+	_, err := client.R().SetContext(context.Background()).Post("/users")
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "POST", attrs["http.method"].AsString())
+	assert.Equal(t, srv.URL+"/users", attrs["http.url"].AsString())
+	assert.Equal(t, "127.0.0.1", attrs["net.peer.name"].AsString())
+	assert.Equal(t, int64(http.StatusCreated), attrs["http.status_code"].AsInt64())
+
+	// legacy attributes are recorded in request event.
+	require.NotEmpty(t, spans[0].Events())
+	event := spans[0].Events()[0]
+	assert.Contains(t, event.Attributes, attribute.String("method", "POST"))
+}
+
+func Test_InjectTracing_HeaderCapture(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Upstream", "users")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := resty.New().SetHostURL(srv.URL).SetHeader("Authorization", "Bearer secret")
+	otelresty.InjectTracing(client, otelresty.WithHeaderCapture(
+		tracing.WithRequestHeaders("Authorization", "X-Tenant"),
+		tracing.WithResponseHeaders("Set-Cookie", "X-Upstream"),
+	))
+
+	_, err := client.R().SetContext(context.Background()).SetHeader("X-Tenant", "acme").Get("/users")
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, []string{"REDACTED"}, attrs["http.request.header.authorization"].AsStringSlice())
+	assert.Equal(t, []string{"acme"}, attrs["http.request.header.x-tenant"].AsStringSlice())
+	assert.Equal(t, []string{"REDACTED"}, attrs["http.response.header.set-cookie"].AsStringSlice())
+	assert.Equal(t, []string{"users"}, attrs["http.response.header.x-upstream"].AsStringSlice())
+}
@@ -0,0 +1,50 @@
+package tracingresty
+
+import (
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+// config helps user to control the tracing middlewares of resty.Client.
+type config struct {
+	// legacyAttributes means recording method and url in request event as
+	// before semconv.
+	legacyAttributes bool
+	// headerCapture is nil if WithHeaderCapture is not used.
+	headerCapture *tracing.HeaderCapture
+}
+
+func defaultConfig() *config {
+	return &config{
+		legacyAttributes: false,
+		headerCapture:    nil,
+	}
+}
+
+type Option interface {
+	apply(*config)
+}
+
+type fnOption func(*config)
+
+func (f fnOption) apply(c *config) { f(c) }
+
+func newFunctionalOption(f func(*config)) Option {
+	return fnOption(f)
+}
+
+// WithLegacyAttributes records method and url in the request event besides
+// the semantic convention attributes, it helps to migrate dashboards and
+// alerts which depend on them.
+func WithLegacyAttributes() Option {
+	return newFunctionalOption(func(c *config) {
+		c.legacyAttributes = true
+	})
+}
+
+// WithHeaderCapture records the allowlist of request and response headers,
+// Authorization, Cookie and Set-Cookie are redacted by default.
+func WithHeaderCapture(opts ...tracing.HeaderCaptureOption) Option {
+	return newFunctionalOption(func(c *config) {
+		c.headerCapture = tracing.NewHeaderCapture(opts...)
+	})
+}
@@ -0,0 +1,146 @@
+package tracingzap
+
+import (
+	"context"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*core)(nil)
+
+// core wraps a zapcore.Core and replaces the Context field with trace fields.
+// Optionally, it mirrors log records into the active span as events.
+type core struct {
+	zapcore.Core
+
+	c       *config
+	counter *spanEventCounter
+
+	// ctx and fields are accumulated by With, they are used to
+	// mirror log records into span events.
+	ctx    context.Context
+	fields []zapcore.Field
+}
+
+// NewCore wraps inner and returns a zapcore.Core which stamps trace_id, span_id
+// and sampled onto log records which carry a Context field. For example:
+//
+//	logger := zap.New(tracingzap.NewCore(inner))
+//	logger.Info("hello", tracingzap.Context(ctx))
+//	// or
+//	logger.With(tracingzap.Context(ctx)).Info("hello")
+//
+// With WithSpanEvents option, log records would also be mirrored into the
+// span in the context as events.
+func NewCore(inner zapcore.Core, opts ...Option) zapcore.Core {
+	c := newConfig(opts...)
+
+	return &core{
+		Core:    inner,
+		c:       c,
+		counter: newSpanEventCounter(c.maxEventsPerSpan),
+	}
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.Core.Enabled(level) || c.mirrorEnabled(level)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	clone := &core{
+		Core:    c.Core.With(c.replaceContextField(fields)),
+		c:       c.c,
+		counter: c.counter,
+		ctx:     c.ctx,
+		fields:  c.fields,
+	}
+
+	if c.c.spanEvents {
+		ctx, idx := contextFromFields(fields)
+		if idx >= 0 {
+			clone.ctx = ctx
+		}
+		clone.fields = appendFieldsWithout(c.fields, fields, idx)
+	}
+
+	return clone
+}
+
+// Check lets the inner core decide whether ent should be written, so that
+// tee, sampler and leveled cores keep working, and adds a checkedCore which
+// stamps trace fields and mirrors ent into the span.
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil && !c.mirrorEnabled(ent.Level) {
+		return ce
+	}
+
+	return ce.AddCore(ent, &checkedCore{core: c, inner: inner})
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.mirrorEnabled(ent.Level) {
+		c.mirror(ent, fields)
+	}
+
+	if !c.Core.Enabled(ent.Level) {
+		return nil
+	}
+
+	return c.Core.Write(ent, c.replaceContextField(fields))
+}
+
+// checkedCore is added by core.Check, it writes through the entry checked by
+// the inner core, which is nil if the inner core drops the entry.
+type checkedCore struct {
+	*core
+	inner *zapcore.CheckedEntry
+}
+
+func (w *checkedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if w.mirrorEnabled(ent.Level) {
+		w.mirror(ent, fields)
+	}
+
+	if w.inner != nil {
+		w.inner.Write(w.replaceContextField(fields)...)
+	}
+
+	return nil
+}
+
+// replaceContextField replaces the Context field with trace fields, fields
+// would be returned directly if there is no Context field.
+func (c *core) replaceContextField(fields []zapcore.Field) []zapcore.Field {
+	ctx, idx := contextFromFields(fields)
+	if idx < 0 {
+		return fields
+	}
+
+	traceFields := fieldsFromContext(ctx, c.c.keys)
+	replaced := make([]zapcore.Field, 0, len(fields)-1+len(traceFields))
+	replaced = append(replaced, fields[:idx]...)
+	replaced = append(replaced, traceFields...)
+	replaced = append(replaced, fields[idx+1:]...)
+
+	return replaced
+}
+
+func (c *core) mirrorEnabled(level zapcore.Level) bool {
+	return c.c.spanEvents && level >= c.c.spanEventLevel
+}
+
+// appendFieldsWithout appends fields except fields[skip] to base, base
+// would never be modified.
+func appendFieldsWithout(base, fields []zapcore.Field, skip int) []zapcore.Field {
+	merged := make([]zapcore.Field, 0, len(base)+len(fields))
+	merged = append(merged, base...)
+	for idx, f := range fields {
+		if idx == skip {
+			continue
+		}
+		merged = append(merged, f)
+	}
+
+	return merged
+}
@@ -0,0 +1,121 @@
+package tracingzap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+	tracingzap "github.com/yeqown/opentelemetry-quake/contrib/zap"
+)
+
+func ExampleNewCore() {
+	logger := zap.New(tracingzap.NewCore(zap.NewExample().Core()))
+
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger.Info("hello", tracingzap.Context(ctx))
+	logger.With(tracingzap.Context(ctx)).Info("world")
+}
+
+func ExampleFields() {
+	logger := zap.NewExample()
+
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger.Info("hello", tracingzap.Fields(ctx)...)
+}
+
+func testContext(t *testing.T) context.Context {
+	tc, err := tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	require.NoError(t, err)
+
+	return tracing.ContextWithTraceContext(context.Background(), tc)
+}
+
+func Test_NewCore(t *testing.T) {
+	inner, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(tracingzap.NewCore(inner))
+	ctx := testContext(t)
+
+	logger.Info("hello", zap.String("k", "v"), tracingzap.Context(ctx))
+	logger.With(tracingzap.Context(ctx)).Info("world")
+	logger.Info("no context")
+	logger.Info("no span", tracingzap.Context(context.Background()))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+	want := map[string]interface{}{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+		"sampled":  true,
+	}
+	assert.Equal(t, map[string]interface{}{
+		"k":        "v",
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+		"sampled":  true,
+	}, entries[0].ContextMap())
+	assert.Equal(t, want, entries[1].ContextMap())
+	assert.Empty(t, entries[2].ContextMap())
+	assert.Empty(t, entries[3].ContextMap())
+}
+
+func Test_Fields(t *testing.T) {
+	ctx := testContext(t)
+
+	fields := tracingzap.Fields(ctx,
+		tracing.WithTraceIDKey("traceId"),
+		tracing.WithSpanIDKey("spanId"),
+		tracing.WithSampledKey(""),
+	)
+	assert.Equal(t, []zap.Field{
+		zap.String("traceId", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		zap.String("spanId", "00f067aa0ba902b7"),
+	}, fields)
+
+	assert.Nil(t, tracingzap.Fields(context.Background()))
+
+	inner, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(tracingzap.NewCore(inner, tracingzap.WithLogKeys(tracing.WithTraceIDKey("traceId"))))
+	logger.Info("hello", tracingzap.Context(ctx))
+	require.Len(t, logs.AllUntimed(), 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logs.AllUntimed()[0].ContextMap()["traceId"])
+}
+
+func Test_NewCore_InnerCheck(t *testing.T) {
+	debug, debugLogs := observer.New(zapcore.DebugLevel)
+	errs, errLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(tracingzap.NewCore(zapcore.NewTee(debug, errs)))
+	ctx := testContext(t)
+
+	logger.Info("info", tracingzap.Context(ctx))
+	logger.Error("error", tracingzap.Context(ctx))
+
+	require.Len(t, debugLogs.AllUntimed(), 2)
+	// the error core only receives entries it enables.
+	entries := errLogs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "error", entries[0].Message)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0].ContextMap()["trace_id"])
+
+	inner, logs := observer.New(zapcore.InfoLevel)
+	sampled := zapcore.NewSamplerWithOptions(inner, time.Minute, 1, 100)
+	logger = zap.New(tracingzap.NewCore(sampled))
+	for i := 0; i < 3; i++ {
+		logger.Info("repeated", tracingzap.Context(ctx))
+	}
+
+	// the sampler drops repeated entries.
+	entries = logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0].ContextMap()["trace_id"])
+}
@@ -0,0 +1,157 @@
+package tracingzap
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+const (
+	spanEventName = "log"
+
+	severityKey = "log.severity"
+	messageKey  = "log.message"
+	loggerKey   = "log.logger"
+)
+
+// mirror records ent and fields into the span in context as an event.
+func (c *core) mirror(ent zapcore.Entry, fields []zapcore.Field) {
+	ctx, idx := contextFromFields(fields)
+	if idx < 0 {
+		ctx = c.ctx
+	}
+	if ctx == nil {
+		return
+	}
+
+	// only recording spans keep events, and they are tracked until ended.
+	if !c.counter.allow(trace.SpanFromContext(ctx)) {
+		return
+	}
+	sp := tracing.SpanFromContext(ctx)
+
+	attrs := make([]attribute.KeyValue, 0, 3+len(c.fields)+len(fields))
+	attrs = append(attrs,
+		attribute.String(severityKey, ent.Level.CapitalString()),
+		attribute.String(messageKey, ent.Message),
+	)
+	if ent.LoggerName != "" {
+		attrs = append(attrs, attribute.String(loggerKey, ent.LoggerName))
+	}
+	attrs = appendFieldAttributes(attrs, c.fields)
+	attrs = appendFieldAttributes(attrs, fields)
+
+	sp.LogFields(spanEventName, attrs...)
+
+	if c.c.errorStatus && ent.Level >= zapcore.ErrorLevel {
+		sp.SetStatus(tracing.Error, ent.Message)
+	}
+}
+
+// appendFieldAttributes converts zap fields into attributes, the Context
+// field would be skipped since its type is zapcore.SkipType.
+func appendFieldAttributes(attrs []attribute.KeyValue, fields []zapcore.Field) []attribute.KeyValue {
+	if len(fields) == 0 {
+		return attrs
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	for k, v := range enc.Fields {
+		attrs = append(attrs, toAttribute(k, v))
+	}
+
+	return attrs
+}
+
+func toAttribute(key string, v interface{}) attribute.KeyValue {
+	switch vv := v.(type) {
+	case string:
+		return attribute.String(key, vv)
+	case bool:
+		return attribute.Bool(key, vv)
+	case int:
+		return attribute.Int(key, vv)
+	case int8:
+		return attribute.Int64(key, int64(vv))
+	case int16:
+		return attribute.Int64(key, int64(vv))
+	case int32:
+		return attribute.Int64(key, int64(vv))
+	case int64:
+		return attribute.Int64(key, vv)
+	case uint8:
+		return attribute.Int64(key, int64(vv))
+	case uint16:
+		return attribute.Int64(key, int64(vv))
+	case uint32:
+		return attribute.Int64(key, int64(vv))
+	case float32:
+		return attribute.Float64(key, float64(vv))
+	case float64:
+		return attribute.Float64(key, vv)
+	case fmt.Stringer:
+		return attribute.String(key, vv.String())
+	}
+
+	return attribute.String(key, fmt.Sprintf("%v", v))
+}
+
+// sweepInterval is the count of newly tracked spans after which the counters
+// of ended spans are cleared.
+const sweepInterval = 1024
+
+// spanEventCounter counts events mirrored into each recording span, so that
+// events could be limited per span. A counter lives until its span is ended,
+// and it's cleared by the next sweep after that.
+type spanEventCounter struct {
+	limit   int
+	counts  sync.Map // trace.SpanID => *spanEventCount
+	tracked int64    // the count of spans ever tracked, it triggers sweeping.
+}
+
+type spanEventCount struct {
+	sp trace.Span
+	n  int64
+}
+
+func newSpanEventCounter(limit int) *spanEventCounter {
+	return &spanEventCounter{limit: limit}
+}
+
+// allow reports whether one more event could be recorded into sp, it's false
+// if sp is not recording.
+func (c *spanEventCounter) allow(sp trace.Span) bool {
+	if !sp.IsRecording() {
+		return false
+	}
+	if c.limit <= 0 {
+		return true
+	}
+
+	v, loaded := c.counts.LoadOrStore(sp.SpanContext().SpanID(), &spanEventCount{sp: sp})
+	if !loaded && atomic.AddInt64(&c.tracked, 1)%sweepInterval == 0 {
+		c.sweep()
+	}
+
+	return atomic.AddInt64(&v.(*spanEventCount).n, 1) <= int64(c.limit)
+}
+
+// sweep clears the counters of ended spans.
+func (c *spanEventCounter) sweep() {
+	c.counts.Range(func(key, value interface{}) bool {
+		if !value.(*spanEventCount).sp.IsRecording() {
+			c.counts.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,111 @@
+package tracingzap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+	tracingzap "github.com/yeqown/opentelemetry-quake/contrib/zap"
+)
+
+func ExampleWithSpanEvents() {
+	logger := zap.New(tracingzap.NewCore(zap.NewExample().Core(),
+		tracingzap.WithSpanEvents(zapcore.WarnLevel),
+		tracingzap.WithErrorStatus(),
+		tracingzap.WithMaxEventsPerSpan(32),
+	))
+
+	ctx, sp := tracing.StartSpan(context.Background(), "example")
+	defer sp.End()
+
+	logger.Error("something wrong", zap.Int("code", 500), tracingzap.Context(ctx))
+}
+
+func Test_WithSpanEvents(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	inner, logs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(tracingzap.NewCore(inner,
+		tracingzap.WithSpanEvents(zapcore.InfoLevel),
+		tracingzap.WithErrorStatus(),
+		tracingzap.WithMaxEventsPerSpan(3),
+	))
+
+	ctx, sp := tracing.StartSpan(context.Background(), "test")
+	logger.Debug("debug is ignored", tracingzap.Context(ctx))
+	logger.With(zap.String("k", "v"), tracingzap.Context(ctx)).Info("info", zap.Int("n", 1))
+	logger.Error("error", zap.Bool("b", true), tracingzap.Context(ctx))
+	logger.Warn("warn", tracingzap.Context(ctx))
+	logger.Warn("dropped since exceeding limit", tracingzap.Context(ctx))
+	logger.Info("no context")
+	sp.End()
+
+	// only error logs are written into inner core.
+	assert.Equal(t, 1, logs.Len())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "error", spans[0].Status().Description)
+
+	events := spans[0].Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "log", events[0].Name)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("log.severity", "INFO"),
+		attribute.String("log.message", "info"),
+		attribute.String("k", "v"),
+		attribute.Int64("n", 1),
+	}, events[0].Attributes)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("log.severity", "ERROR"),
+		attribute.String("log.message", "error"),
+		attribute.Bool("b", true),
+	}, events[1].Attributes)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("log.severity", "WARN"),
+		attribute.String("log.message", "warn"),
+	}, events[2].Attributes)
+}
+
+func Test_WithMaxEventsPerSpan_ManySpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	inner, _ := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(tracingzap.NewCore(inner,
+		tracingzap.WithSpanEvents(zapcore.InfoLevel),
+		tracingzap.WithMaxEventsPerSpan(2),
+	))
+
+	// the limit still works while a lot of spans are in flight.
+	ctxs := make([]context.Context, 5000)
+	spans := make([]tracing.Span, len(ctxs))
+	for i := range ctxs {
+		ctxs[i], spans[i] = tracing.StartSpan(context.Background(), "test")
+		logger.Info("first", tracingzap.Context(ctxs[i]))
+	}
+	for i := range ctxs {
+		logger.Info("second", tracingzap.Context(ctxs[i]))
+		logger.Info("dropped", tracingzap.Context(ctxs[i]))
+		spans[i].End()
+	}
+
+	ended := sr.Ended()
+	require.Len(t, ended, len(ctxs))
+	for _, sp := range ended {
+		require.Len(t, sp.Events(), 2)
+	}
+}
@@ -0,0 +1,69 @@
+package tracingzap
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+// contextFieldKey is the key of the field which carries context.Context,
+// it would never be written by encoders since its type is zapcore.SkipType.
+const contextFieldKey = "opentelemetry.zap.context"
+
+// Context returns a zap.Field which carries ctx. Cores created by NewCore
+// replace it with trace fields of ctx, other cores just skip it.
+//
+//	logger.Info("hello", tracingzap.Context(ctx))
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{
+		Key:       contextFieldKey,
+		Type:      zapcore.SkipType,
+		Interface: ctx,
+	}
+}
+
+// Fields returns trace_id, span_id and sampled fields of the span in ctx,
+// nil would be returned if there is no valid span in ctx.
+//
+//	logger.Info("hello", tracingzap.Fields(ctx)...)
+func Fields(ctx context.Context, opts ...tracing.LogKeysOption) []zap.Field {
+	return fieldsFromContext(ctx, tracing.DefaultLogKeys().With(opts...))
+}
+
+func fieldsFromContext(ctx context.Context, keys tracing.LogKeys) []zap.Field {
+	tc := tracing.TraceContextFromContext(ctx)
+	if !tc.IsValid() {
+		return nil
+	}
+
+	fields := make([]zap.Field, 0, 3)
+	if keys.TraceID != "" {
+		fields = append(fields, zap.String(keys.TraceID, tc.TraceID))
+	}
+	if keys.SpanID != "" {
+		fields = append(fields, zap.String(keys.SpanID, tc.SpanID))
+	}
+	if keys.Sampled != "" {
+		fields = append(fields, zap.Bool(keys.Sampled, tc.Sampled()))
+	}
+
+	return fields
+}
+
+// contextFromFields returns the context carried by Context field and its index,
+// index is -1 if there is no such field.
+func contextFromFields(fields []zapcore.Field) (context.Context, int) {
+	for idx, f := range fields {
+		if f.Key != contextFieldKey || f.Type != zapcore.SkipType {
+			continue
+		}
+		if ctx, ok := f.Interface.(context.Context); ok {
+			return ctx, idx
+		}
+	}
+
+	return nil, -1
+}
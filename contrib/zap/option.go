@@ -0,0 +1,86 @@
+package tracingzap
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+)
+
+// config helps user to control which fields would be stamped onto log
+// records. Empty key means the field would be omitted. It also controls
+// whether log records should be mirrored into the active span as events.
+type config struct {
+	keys tracing.LogKeys
+
+	spanEvents       bool          // mirror log records into span events or not.
+	spanEventLevel   zapcore.Level // the minimum level to mirror.
+	errorStatus      bool          // set span status to Error while mirroring error logs.
+	maxEventsPerSpan int           // the maximum events mirrored into one span, 0 means no limit.
+}
+
+func defaultConfig() *config {
+	return &config{
+		keys:             tracing.DefaultLogKeys(),
+		spanEvents:       false,
+		spanEventLevel:   zapcore.InfoLevel,
+		errorStatus:      false,
+		maxEventsPerSpan: 128,
+	}
+}
+
+type Option interface {
+	apply(*config)
+}
+
+type fnOption func(*config)
+
+func (f fnOption) apply(c *config) { f(c) }
+
+func newFunctionalOption(f func(*config)) Option {
+	return fnOption(f)
+}
+
+// WithLogKeys sets the field names of trace context, default are "trace_id",
+// "span_id" and "sampled".
+func WithLogKeys(opts ...tracing.LogKeysOption) Option {
+	return newFunctionalOption(func(c *config) {
+		c.keys = c.keys.With(opts...)
+	})
+}
+
+// WithSpanEvents mirrors log records at or above level into the active span
+// as events, each event carries severity, message and structured fields.
+// It only works with NewCore.
+func WithSpanEvents(level zapcore.Level) Option {
+	return newFunctionalOption(func(c *config) {
+		c.spanEvents = true
+		c.spanEventLevel = level
+	})
+}
+
+// WithErrorStatus sets the status of the active span to Error while
+// mirroring log records at or above zapcore.ErrorLevel.
+func WithErrorStatus() Option {
+	return newFunctionalOption(func(c *config) {
+		c.errorStatus = true
+	})
+}
+
+// WithMaxEventsPerSpan limits the count of events mirrored into one span,
+// so that chatty loops don't blow up span size. Default is 128, 0 means no limit.
+func WithMaxEventsPerSpan(n int) Option {
+	return newFunctionalOption(func(c *config) {
+		if n >= 0 {
+			c.maxEventsPerSpan = n
+		}
+	})
+}
+
+func newConfig(opts ...Option) *config {
+	c := defaultConfig()
+	for _, o := range opts {
+		o.apply(c)
+	}
+
+	return c
+}
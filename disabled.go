@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	// _disabled is 1 if tracing is disabled by WithDisabled or OTEL_SDK_DISABLED.
+	_disabled int32
+
+	// _initialTracerProvider is the global TracerProvider of otel before any
+	// provider is set, StartSpan does nothing while it's still in use.
+	_initialTracerProvider = otel.GetTracerProvider()
+)
+
+func setDisabled(disabled bool) {
+	if disabled {
+		atomic.StoreInt32(&_disabled, 1)
+		return
+	}
+
+	atomic.StoreInt32(&_disabled, 0)
+}
+
+// Disabled reports whether tracing is disabled by WithDisabled or
+// OTEL_SDK_DISABLED.
+func Disabled() bool {
+	return atomic.LoadInt32(&_disabled) == 1
+}
+
+// Noop reports whether StartSpan would return a noop span, it's true if
+// tracing is disabled or no TracerProvider has been set (Setup is never
+// called). Contrib packages should skip the work only for tracing, such as:
+// extracting carriers and marshalling payloads, if it's true.
+func Noop() bool {
+	return Disabled() || otel.GetTracerProvider() == _initialTracerProvider
+}
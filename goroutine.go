@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ context.Context = detachedContext{}
+
+// detachedContext keeps values of parent, but never be cancelled
+// and has no deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (d detachedContext) Deadline() (deadline time.Time, ok bool) { return time.Time{}, false }
+func (d detachedContext) Done() <-chan struct{}                   { return nil }
+func (d detachedContext) Err() error                              { return nil }
+func (d detachedContext) Value(key interface{}) interface{}       { return d.parent.Value(key) }
+
+// Detach returns a context which keeps the span, baggage and other values
+// of ctx, but drops the deadline and cancellation of ctx. It is useful to
+// pass the trace into background goroutines which should survive after
+// the request has been finished.
+func Detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return detachedContext{parent: ctx}
+}
+
+// Go starts a goroutine to run fn in a child span of the span in ctx. ctx is
+// detached before passing to fn, so fn would not be cancelled while ctx is
+// done. Use WithNewRoot to start a new root span which links to the span in
+// ctx instead of a child span. Panic in fn is recovered and recorded.
+func Go(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...SpanStartOption) {
+	ctx = Detach(ctx)
+
+	go func() {
+		_ = Run(ctx, name, fn, WithStartOptions(opts...))
+	}()
+}
+
+// Group is a collection of goroutines working on subtasks of the same
+// overall task, it works like golang.org/x/sync/errgroup.Group, but each
+// goroutine runs in a child span, and panic is recovered and recorded.
+type Group struct {
+	cancel func()
+	opts   []SpanStartOption
+
+	ctx     context.Context
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a new Group and an associated context derived from ctx.
+// The derived context is cancelled the first time a function passed to Go
+// returns a non-nil error or panics, or the first time Wait returns,
+// whichever occurs first. opts are used to start the span of each goroutine.
+func NewGroup(ctx context.Context, opts ...SpanStartOption) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Group{
+		cancel: cancel,
+		opts:   opts,
+		ctx:    ctx,
+	}, ctx
+}
+
+// Go calls fn in a new goroutine with a child span named name. The first
+// call to return a non-nil error cancels the group, and its error will be
+// returned by Wait.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if err := Run(g.ctx, name, fn, WithStartOptions(g.opts...)); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the Go method have returned,
+// then returns the first non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
+}
@@ -0,0 +1,178 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ExampleGo() {
+	ctx, sp := tracing.StartSpan(context.Background(), "handler")
+	defer sp.End()
+
+	tracing.Go(ctx, "background", func(ctx context.Context) error {
+		// ctx would not be cancelled even if the handler has returned.
+		return nil
+	})
+}
+
+func ExampleNewGroup() {
+	g, ctx := tracing.NewGroup(context.Background())
+	g.Go("task1", func(ctx context.Context) error { return nil })
+	g.Go("task2", func(ctx context.Context) error { return nil })
+
+	if err := g.Wait(); err != nil {
+		// handle error
+	}
+	_ = ctx
+}
+
+type goroutineTestSuite struct {
+	suite.Suite
+
+	recorder *tracetest.SpanRecorder
+	previous trace.TracerProvider
+}
+
+func (s *goroutineTestSuite) SetupSuite() {
+	s.previous = otel.GetTracerProvider()
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+}
+
+func (s *goroutineTestSuite) TearDownSuite() {
+	otel.SetTracerProvider(s.previous)
+}
+
+func (s *goroutineTestSuite) endedSpan(name string) sdktrace.ReadOnlySpan {
+	for _, sp := range s.recorder.Ended() {
+		if sp.Name() == name {
+			return sp
+		}
+	}
+
+	return nil
+}
+
+func (s *goroutineTestSuite) Test_Detach() {
+	t := s.T()
+
+	ctx, sp := tracing.StartSpan(context.Background(), "parent")
+	defer sp.End()
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	cancel()
+
+	detached := tracing.Detach(ctx)
+	assert.Error(t, ctx.Err())
+	assert.NoError(t, detached.Err())
+	assert.Nil(t, detached.Done())
+	_, ok := detached.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, tracing.TraceContextFromContext(ctx), tracing.TraceContextFromContext(detached))
+}
+
+func (s *goroutineTestSuite) Test_Go() {
+	t := s.T()
+
+	ctx, sp := tracing.StartSpan(context.Background(), "go.parent")
+	ctx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	release := make(chan struct{})
+	tracing.Go(ctx, "go.child", func(ctx context.Context) error {
+		<-release
+		assert.NoError(t, ctx.Err())
+		close(done)
+		return nil
+	})
+
+	// the request has finished before the background goroutine.
+	cancel()
+	sp.End()
+	close(release)
+	<-done
+
+	require.Eventually(t, func() bool { return s.endedSpan("go.child") != nil }, time.Second, time.Millisecond)
+	child := s.endedSpan("go.child")
+	assert.Equal(t, sp.SpanContext().TraceID, child.SpanContext().TraceID().String())
+	assert.Equal(t, sp.SpanContext().SpanID, child.Parent().SpanID().String())
+}
+
+func (s *goroutineTestSuite) Test_Go_newRoot() {
+	t := s.T()
+
+	ctx, sp := tracing.StartSpan(context.Background(), "go.root.parent")
+	defer sp.End()
+
+	done := make(chan struct{})
+	tracing.Go(ctx, "go.root", func(ctx context.Context) error {
+		defer close(done)
+		panic("recovered")
+	}, tracing.WithNewRoot())
+	<-done
+
+	require.Eventually(t, func() bool { return s.endedSpan("go.root") != nil }, time.Second, time.Millisecond)
+	root := s.endedSpan("go.root")
+	assert.NotEqual(t, sp.SpanContext().TraceID, root.SpanContext().TraceID().String())
+	assert.False(t, root.Parent().IsValid())
+	require.Len(t, root.Links(), 1)
+	assert.Equal(t, sp.SpanContext().SpanID, root.Links()[0].SpanContext.SpanID().String())
+	assert.Equal(t, codes.Error, root.Status().Code)
+}
+
+func (s *goroutineTestSuite) Test_Group() {
+	t := s.T()
+
+	ctx, sp := tracing.StartSpan(context.Background(), "group.parent")
+	defer sp.End()
+
+	var finished int32
+	g, gctx := tracing.NewGroup(ctx)
+	g.Go("group.ok", func(ctx context.Context) error {
+		atomic.AddInt32(&finished, 1)
+		return nil
+	})
+	errFailed := errors.New("failed")
+	g.Go("group.failed", func(ctx context.Context) error {
+		return errFailed
+	})
+	g.Go("group.cancelled", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, errFailed, g.Wait())
+	assert.Error(t, gctx.Err())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finished))
+
+	for _, name := range []string{"group.ok", "group.failed", "group.cancelled"} {
+		child := s.endedSpan(name)
+		require.NotNil(t, child, name)
+		assert.Equal(t, sp.SpanContext().SpanID, child.Parent().SpanID().String())
+	}
+	assert.Equal(t, codes.Error, s.endedSpan("group.failed").Status().Code)
+
+	g2, _ := tracing.NewGroup(ctx)
+	g2.Go("group.panic", func(ctx context.Context) error {
+		panic("boom")
+	})
+	var pe *tracing.PanicError
+	assert.True(t, errors.As(g2.Wait(), &pe))
+}
+
+func Test_goroutineSuite(t *testing.T) {
+	suite.Run(t, new(goroutineTestSuite))
+}
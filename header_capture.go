@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// redactedValue replaces the values of redacted headers.
+const redactedValue = "REDACTED"
+
+// defaultRedactedHeaders carry credentials, they're always redacted unless
+// WithUnredactedHeaders is used.
+var defaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+type HeaderCaptureOption interface {
+	apply(h *HeaderCapture)
+}
+
+type fnHeaderCaptureOption func(h *HeaderCapture)
+
+func (fn fnHeaderCaptureOption) apply(h *HeaderCapture) { fn(h) }
+func newFnHeaderCaptureOption(fn func(h *HeaderCapture)) HeaderCaptureOption {
+	return fnHeaderCaptureOption(fn)
+}
+
+// WithRequestHeaders sets the allowlist of request headers to record.
+func WithRequestHeaders(names ...string) HeaderCaptureOption {
+	return newFnHeaderCaptureOption(func(h *HeaderCapture) {
+		h.request = append(h.request, canonicalHeaders(names)...)
+	})
+}
+
+// WithResponseHeaders sets the allowlist of response headers to record.
+func WithResponseHeaders(names ...string) HeaderCaptureOption {
+	return newFnHeaderCaptureOption(func(h *HeaderCapture) {
+		h.response = append(h.response, canonicalHeaders(names)...)
+	})
+}
+
+// WithRedactedHeaders redacts headers besides Authorization, Cookie and
+// Set-Cookie, such as: X-Api-Key.
+func WithRedactedHeaders(names ...string) HeaderCaptureOption {
+	return newFnHeaderCaptureOption(func(h *HeaderCapture) {
+		for _, name := range canonicalHeaders(names) {
+			h.redacted[name] = struct{}{}
+		}
+	})
+}
+
+// WithUnredactedHeaders records the values of headers which are redacted by
+// default, such as: Cookie. Use it only if the values are not credentials.
+func WithUnredactedHeaders(names ...string) HeaderCaptureOption {
+	return newFnHeaderCaptureOption(func(h *HeaderCapture) {
+		for _, name := range canonicalHeaders(names) {
+			delete(h.redacted, name)
+		}
+	})
+}
+
+// HeaderCapture records the allowlist of HTTP headers as
+// http.request.header.<name> and http.response.header.<name> attributes,
+// <name> is the lowercase header name. Values of Authorization, Cookie and
+// Set-Cookie are redacted by default. It's shared by contrib packages.
+type HeaderCapture struct {
+	request  []string
+	response []string
+	redacted map[string]struct{}
+}
+
+// NewHeaderCapture creates a HeaderCapture, no header is recorded unless
+// WithRequestHeaders or WithResponseHeaders is used.
+func NewHeaderCapture(opts ...HeaderCaptureOption) *HeaderCapture {
+	h := &HeaderCapture{
+		request:  nil,
+		response: nil,
+		redacted: make(map[string]struct{}, len(defaultRedactedHeaders)),
+	}
+	for _, name := range canonicalHeaders(defaultRedactedHeaders) {
+		h.redacted[name] = struct{}{}
+	}
+	for _, opt := range opts {
+		opt.apply(h)
+	}
+
+	return h
+}
+
+// RequestAttributes returns the attributes of allowed headers in header of
+// request.
+func (h *HeaderCapture) RequestAttributes(header http.Header) []attribute.KeyValue {
+	return h.attributes("http.request.header.", h.request, header)
+}
+
+// ResponseAttributes returns the attributes of allowed headers in header of
+// response.
+func (h *HeaderCapture) ResponseAttributes(header http.Header) []attribute.KeyValue {
+	return h.attributes("http.response.header.", h.response, header)
+}
+
+func (h *HeaderCapture) attributes(prefix string, allowed []string, header http.Header) []attribute.KeyValue {
+	if len(allowed) == 0 || len(header) == 0 {
+		return nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(allowed))
+	for _, name := range allowed {
+		values, ok := header[name]
+		if !ok {
+			continue
+		}
+		if _, redacted := h.redacted[name]; redacted {
+			values = []string{redactedValue}
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(name), values))
+	}
+
+	return attrs
+}
+
+func canonicalHeaders(names []string) []string {
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			canonical = append(canonical, http.CanonicalHeaderKey(name))
+		}
+	}
+
+	return canonical
+}
@@ -0,0 +1,45 @@
+package tracing_test
+
+import (
+	"net/http"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func Test_HeaderCapture(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Authorization", "Bearer secret")
+	header.Set("Cookie", "session=secret")
+	header.Set("X-Api-Key", "secret")
+	header.Add("X-Forwarded-For", "10.0.0.1")
+	header.Add("X-Forwarded-For", "10.0.0.2")
+
+	h := tracing.NewHeaderCapture(
+		tracing.WithRequestHeaders("content-type", "authorization", "cookie", "x-api-key", "x-forwarded-for", "x-missing"),
+		tracing.WithRedactedHeaders("X-Api-Key"),
+	)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.content-type", []string{"application/json"}),
+		attribute.StringSlice("http.request.header.authorization", []string{"REDACTED"}),
+		attribute.StringSlice("http.request.header.cookie", []string{"REDACTED"}),
+		attribute.StringSlice("http.request.header.x-api-key", []string{"REDACTED"}),
+		attribute.StringSlice("http.request.header.x-forwarded-for", []string{"10.0.0.1", "10.0.0.2"}),
+	}, h.RequestAttributes(header))
+	// response headers are not in allowlist.
+	assert.Empty(t, h.ResponseAttributes(header))
+
+	h = tracing.NewHeaderCapture(
+		tracing.WithResponseHeaders("Set-Cookie"),
+		tracing.WithUnredactedHeaders("Set-Cookie"),
+	)
+	response := http.Header{}
+	response.Set("Set-Cookie", "theme=dark")
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.StringSlice("http.response.header.set-cookie", []string{"theme=dark"}),
+	}, h.ResponseAttributes(response))
+}
@@ -0,0 +1,215 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InboundDecision decides how to deal with trace context from untrusted
+// inbound requests.
+type InboundDecision uint8
+
+const (
+	// InboundHonor uses incoming trace context as parent of server span.
+	InboundHonor InboundDecision = iota
+	// InboundIgnore drops incoming trace context, server span is a new root.
+	InboundIgnore
+	// InboundIgnoreAndLink starts server span as a new root which links to
+	// incoming trace context, so that the caller's trace is still reachable.
+	InboundIgnoreAndLink
+)
+
+// InboundRequest is the inbound request which is checked by trust predicates.
+type InboundRequest struct {
+	// RemoteAddr is the address of peer, such as: 10.0.0.1:5678.
+	RemoteAddr string
+	// Carrier carries headers or metadata of request.
+	Carrier TraceContextCarrier
+}
+
+type InboundPolicyOption interface {
+	apply(p *InboundPolicy)
+}
+
+type fnInboundPolicyOption func(p *InboundPolicy)
+
+func (fn fnInboundPolicyOption) apply(p *InboundPolicy) { fn(p) }
+func newFnInboundPolicyOption(fn func(p *InboundPolicy)) InboundPolicyOption {
+	return fnInboundPolicyOption(fn)
+}
+
+// WithTrustedCIDRs trusts requests from peers in cidrs, such as: 10.0.0.0/8.
+// Invalid cidrs are ignored with a warning.
+func WithTrustedCIDRs(cidrs ...string) InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		nets := make([]*net.IPNet, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				fmt.Printf("[med/opentelemetry] WARNNING: invalid trusted cidr %q: %v\n", cidr, err)
+				continue
+			}
+			nets = append(nets, ipNet)
+		}
+
+		p.trusted = append(p.trusted, func(r InboundRequest) bool {
+			ip := remoteIP(r.RemoteAddr)
+			if ip == nil {
+				return false
+			}
+			for _, ipNet := range nets {
+				if ipNet.Contains(ip) {
+					return true
+				}
+			}
+			return false
+		})
+	})
+}
+
+// WithTrustedHeader trusts requests which carry header, such as: an internal
+// authentication header which is stripped by edge proxies.
+func WithTrustedHeader(header string) InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		p.trusted = append(p.trusted, func(r InboundRequest) bool {
+			return r.Carrier != nil && r.Carrier.Get(header) != ""
+		})
+	})
+}
+
+// WithTrustedFunc trusts requests which fn returns true for.
+func WithTrustedFunc(fn func(r InboundRequest) bool) InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		if fn != nil {
+			p.trusted = append(p.trusted, fn)
+		}
+	})
+}
+
+// WithUntrustedDecision sets the decision of untrusted requests. Default:
+// InboundIgnore.
+func WithUntrustedDecision(decision InboundDecision) InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		p.untrusted = decision
+	})
+}
+
+// WithStripBaggage drops baggage of untrusted requests, including sentry-*
+// baggage entries.
+func WithStripBaggage() InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		p.stripBaggage = true
+	})
+}
+
+// WithStripTraceState drops tracestate of untrusted requests.
+func WithStripTraceState() InboundPolicyOption {
+	return newFnInboundPolicyOption(func(p *InboundPolicy) {
+		p.stripTraceState = true
+	})
+}
+
+// InboundPolicy decides whether to trust trace context of inbound requests.
+// A request is trusted if any trust predicate matches, trace context of
+// trusted requests is always honoured. Others are dealt with by the
+// untrusted decision, and baggage and tracestate could be stripped.
+//
+// Without any trust predicate, all requests are untrusted.
+type InboundPolicy struct {
+	trusted         []func(r InboundRequest) bool
+	untrusted       InboundDecision
+	stripBaggage    bool
+	stripTraceState bool
+}
+
+// NewInboundPolicy creates an InboundPolicy.
+func NewInboundPolicy(opts ...InboundPolicyOption) *InboundPolicy {
+	p := &InboundPolicy{
+		trusted:         nil,
+		untrusted:       InboundIgnore,
+		stripBaggage:    false,
+		stripTraceState: false,
+	}
+	for _, opt := range opts {
+		opt.apply(p)
+	}
+
+	return p
+}
+
+// Trusted reports whether r is trusted.
+func (p *InboundPolicy) Trusted(r InboundRequest) bool {
+	for _, trusted := range p.trusted {
+		if trusted(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract extracts trace context from r.Carrier by GetPropagator and applies
+// the policy. The returned option should be passed to StartSpan of server
+// span, it starts a new root for InboundIgnoreAndLink.
+func (p *InboundPolicy) Extract(ctx context.Context, r InboundRequest) (context.Context, SpanStartOption) {
+	extracted := GetPropagator().Extract(ctx, r.Carrier)
+	if p.Trusted(r) {
+		return extracted, noopSpanStartOption{}
+	}
+
+	// request ID of untrusted requests should not derive trace ID either.
+	extracted = context.WithValue(extracted, untrustedInboundKey{}, true)
+	sc := trace.SpanContextFromContext(extracted)
+	if p.stripTraceState {
+		sc = sc.WithTraceState(trace.TraceState{})
+	}
+	if p.stripBaggage {
+		extracted = baggage.ContextWithoutBaggage(extracted)
+		extracted = context.WithValue(extracted, sentryDSCKey{}, nil)
+	}
+
+	switch p.untrusted {
+	case InboundIgnore:
+		return trace.ContextWithSpanContext(extracted, trace.SpanContext{}), noopSpanStartOption{}
+	case InboundIgnoreAndLink:
+		if !sc.IsValid() {
+			return extracted, noopSpanStartOption{}
+		}
+		return trace.ContextWithRemoteSpanContext(extracted, sc), WithNewRoot()
+	default:
+		if !sc.IsValid() {
+			return extracted, noopSpanStartOption{}
+		}
+		return trace.ContextWithRemoteSpanContext(extracted, sc), noopSpanStartOption{}
+	}
+}
+
+// untrustedInboundKey marks the context of an untrusted inbound request.
+type untrustedInboundKey struct{}
+
+// untrustedInbound reports whether ctx is extracted from an untrusted request
+// by InboundPolicy.
+func untrustedInbound(ctx context.Context) bool {
+	untrusted, _ := ctx.Value(untrustedInboundKey{}).(bool)
+	return untrusted
+}
+
+// noopSpanStartOption changes nothing, it's returned by InboundPolicy.Extract
+// so that callers always have an option to pass.
+type noopSpanStartOption struct{}
+
+func (noopSpanStartOption) apply(*startSpanOption) {}
+
+// remoteIP parses ip from addr which could be either ip:port or ip.
+func remoteIP(addr string) net.IP {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	return net.ParseIP(strings.Trim(addr, "[]"))
+}
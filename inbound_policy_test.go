@@ -0,0 +1,140 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ExampleNewInboundPolicy() {
+	// trust trace context from internal network or internal gateway, and
+	// link to others.
+	policy := tracing.NewInboundPolicy(
+		tracing.WithTrustedCIDRs("10.0.0.0/8", "192.168.0.0/16"),
+		tracing.WithTrustedHeader("X-Internal-Auth"),
+		tracing.WithUntrustedDecision(tracing.InboundIgnoreAndLink),
+		tracing.WithStripBaggage(),
+	)
+	_ = policy
+}
+
+func Test_InboundPolicy(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previousProvider)
+
+	previousPropagator := tracing.GetPropagator()
+	tracing.SetPropagator(tracing.NewCompositePropagator(
+		tracing.W3CPropagator(),
+		tracing.FromTextMapPropagator(propagation.Baggage{}),
+	))
+	defer tracing.SetPropagator(previousPropagator)
+
+	incoming := "994f3d6cebfc1b8f19c52a8c687ab5f3"
+	newHeader := func() http.Header {
+		h := http.Header{}
+		h.Set("traceparent", "00-"+incoming+"-0c77d121fee1c02a-01")
+		h.Set("tracestate", "vendor=value")
+		h.Set("baggage", "user=alice")
+		return h
+	}
+
+	tests := []struct {
+		name       string
+		policy     *tracing.InboundPolicy
+		remoteAddr string
+		header     http.Header
+
+		wantTraceID    bool // trace ID is the incoming one.
+		wantLink       bool
+		wantTraceState string
+		wantBaggage    string
+	}{
+		{
+			name:        "trusted cidr",
+			policy:      tracing.NewInboundPolicy(tracing.WithTrustedCIDRs("invalid", "10.0.0.0/8")),
+			remoteAddr:  "10.1.2.3:5678",
+			wantTraceID: true, wantTraceState: "vendor=value", wantBaggage: "alice",
+		},
+		{
+			name:   "trusted header",
+			policy: tracing.NewInboundPolicy(tracing.WithTrustedHeader("X-Internal-Auth")),
+			header: func() http.Header {
+				h := newHeader()
+				h.Set("X-Internal-Auth", "token")
+				return h
+			}(),
+			remoteAddr:  "1.2.3.4:5678",
+			wantTraceID: true, wantTraceState: "vendor=value", wantBaggage: "alice",
+		},
+		{
+			name:        "untrusted ignore",
+			policy:      tracing.NewInboundPolicy(tracing.WithTrustedCIDRs("10.0.0.0/8")),
+			remoteAddr:  "1.2.3.4:5678",
+			wantBaggage: "alice",
+		},
+		{
+			name: "untrusted ignore and link",
+			policy: tracing.NewInboundPolicy(
+				tracing.WithUntrustedDecision(tracing.InboundIgnoreAndLink),
+				tracing.WithStripBaggage(),
+			),
+			remoteAddr: "[2001:db8::1]:5678",
+			wantLink:   true,
+		},
+		{
+			name: "untrusted honor and strip",
+			policy: tracing.NewInboundPolicy(
+				tracing.WithUntrustedDecision(tracing.InboundHonor),
+				tracing.WithStripBaggage(),
+				tracing.WithStripTraceState(),
+			),
+			remoteAddr:  "1.2.3.4:5678",
+			wantTraceID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = newHeader()
+			}
+
+			ctx, opt := tt.policy.Extract(context.Background(), tracing.InboundRequest{
+				RemoteAddr: tt.remoteAddr,
+				Carrier:    header,
+			})
+			ctx, sp := tracing.StartSpan(ctx, "server", tracing.WithSpanKind(tracing.SpanKindServer), opt)
+			sp.End()
+
+			assert.Equal(t, tt.wantBaggage, baggage.FromContext(ctx).Member("user").Value())
+
+			spans := recorder.Ended()
+			ro := spans[len(spans)-1]
+			assert.Equal(t, tt.wantTraceID, ro.SpanContext().TraceID().String() == incoming)
+			if tt.wantTraceID {
+				assert.Equal(t, tt.wantTraceState, ro.Parent().TraceState().String())
+			}
+			if tt.wantLink {
+				require.Len(t, ro.Links(), 1)
+				assert.Equal(t, incoming, ro.Links()[0].SpanContext.TraceID().String())
+			} else {
+				assert.Empty(t, ro.Links())
+			}
+			assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+		})
+	}
+}
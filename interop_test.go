@@ -0,0 +1,110 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type interopTestSuite struct {
+	suite.Suite
+
+	recorder *tracetest.SpanRecorder
+	previous trace.TracerProvider
+	// tracer plays the role of other instrumentation, such as: otelhttp.
+	tracer trace.Tracer
+}
+
+func (s *interopTestSuite) SetupSuite() {
+	s.previous = otel.GetTracerProvider()
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+	s.tracer = otel.Tracer("third-party")
+}
+
+func (s *interopTestSuite) TearDownSuite() {
+	otel.SetTracerProvider(s.previous)
+}
+
+func (s *interopTestSuite) Test_SpanFromContext_OtelSpan() {
+	ctx, parent := s.tracer.Start(context.Background(), "parent")
+	defer parent.End()
+	ctx, other := s.tracer.Start(ctx, "other")
+
+	sp := tracing.SpanFromContext(ctx)
+	tc := sp.SpanContext()
+	s.True(tc.IsValid())
+	s.Equal(other.SpanContext().TraceID().String(), tc.TraceID)
+	s.Equal(other.SpanContext().SpanID().String(), tc.SpanID)
+	s.Equal(parent.SpanContext().SpanID().String(), tc.ParentSpanID)
+
+	// the wrapped span writes to the otel span.
+	sp.SetAttributes(attribute.String("interop", "yes"))
+	other.End()
+
+	ended := s.recorder.Ended()
+	s.Require().NotEmpty(ended)
+	s.Contains(ended[len(ended)-1].Attributes(), attribute.String("interop", "yes"))
+}
+
+func (s *interopTestSuite) Test_SpanFromContext_RemoteSpan() {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+
+	tc := tracing.TraceContextFromContext(ctx)
+	s.True(tc.IsValid())
+	s.True(tc.IsRemote())
+	s.Equal(sc.TraceID().String(), tc.TraceID)
+}
+
+func (s *interopTestSuite) Test_StartSpan_VisibleToOtel() {
+	ctx, sp := tracing.StartSpan(context.Background(), "ours")
+	defer sp.End()
+
+	otelSpan := trace.SpanFromContext(ctx)
+	s.Equal(sp.SpanContext().SpanID, otelSpan.SpanContext().SpanID().String())
+	s.True(otelSpan.IsRecording())
+
+	// spans started by other instrumentation are children of ours.
+	_, child := s.tracer.Start(ctx, "child")
+	child.End()
+	ended := s.recorder.Ended()
+	s.Require().NotEmpty(ended)
+	s.Equal(sp.SpanContext().SpanID, ended[len(ended)-1].Parent().SpanID().String())
+}
+
+func (s *interopTestSuite) Test_StartSpan_ChildSpanCount() {
+	ctx, parent := tracing.StartSpan(context.Background(), "parent")
+	_, child1 := tracing.StartSpan(ctx, "child1")
+	child1.End()
+	_, child2 := tracing.StartSpan(ctx, "child2")
+	child2.End()
+	parent.End()
+
+	ended := s.recorder.Ended()
+	s.Require().NotEmpty(ended)
+	last := ended[len(ended)-1]
+	s.Equal("parent", last.Name())
+	s.Equal(2, last.ChildSpanCount())
+}
+
+func (s *interopTestSuite) Test_SpanFromContext_Empty() {
+	s.False(tracing.SpanFromContext(context.Background()).SpanContext().IsValid())
+}
+
+func Test_interop(t *testing.T) {
+	suite.Run(t, new(interopTestSuite))
+}
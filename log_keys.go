@@ -0,0 +1,63 @@
+package tracing
+
+// LogKeys are the field names of trace context which are stamped onto log
+// records by log contribs, such as: contrib/log, contrib/logrus and
+// contrib/zap. Empty key means the field would be omitted.
+type LogKeys struct {
+	TraceID string
+	SpanID  string
+	Sampled string
+}
+
+// DefaultLogKeys returns LogKeys with "trace_id", "span_id" and "sampled".
+func DefaultLogKeys() LogKeys {
+	return LogKeys{
+		TraceID: "trace_id",
+		SpanID:  "span_id",
+		Sampled: "sampled",
+	}
+}
+
+// With returns a copy of k with opts applied.
+func (k LogKeys) With(opts ...LogKeysOption) LogKeys {
+	for _, o := range opts {
+		o.apply(&k)
+	}
+
+	return k
+}
+
+// LogKeysOption changes the field names of LogKeys, log contribs accept it
+// directly, such as: tracinglog.NewWriter(ctx, w, tracing.WithTraceIDKey("traceId")).
+type LogKeysOption interface {
+	apply(*LogKeys)
+}
+
+type fnLogKeysOption func(*LogKeys)
+
+func (f fnLogKeysOption) apply(k *LogKeys) { f(k) }
+
+func newFnLogKeysOption(f func(*LogKeys)) LogKeysOption {
+	return fnLogKeysOption(f)
+}
+
+// WithTraceIDKey sets the field name of trace ID, default is "trace_id".
+func WithTraceIDKey(key string) LogKeysOption {
+	return newFnLogKeysOption(func(k *LogKeys) {
+		k.TraceID = key
+	})
+}
+
+// WithSpanIDKey sets the field name of span ID, default is "span_id".
+func WithSpanIDKey(key string) LogKeysOption {
+	return newFnLogKeysOption(func(k *LogKeys) {
+		k.SpanID = key
+	})
+}
+
+// WithSampledKey sets the field name of sampled flag, default is "sampled".
+func WithSampledKey(key string) LogKeysOption {
+	return newFnLogKeysOption(func(k *LogKeys) {
+		k.Sampled = key
+	})
+}
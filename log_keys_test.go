@@ -0,0 +1,17 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LogKeys(t *testing.T) {
+	k := DefaultLogKeys()
+	assert.Equal(t, LogKeys{TraceID: "trace_id", SpanID: "span_id", Sampled: "sampled"}, k)
+
+	k2 := k.With(WithTraceIDKey("traceId"), WithSpanIDKey("spanId"), WithSampledKey(""))
+	assert.Equal(t, LogKeys{TraceID: "traceId", SpanID: "spanId", Sampled: ""}, k2)
+	// k is not changed.
+	assert.Equal(t, "trace_id", k.TraceID)
+}
@@ -0,0 +1,174 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Counter is an instrument that records increasing values, such as:
+// the count of requests.
+type Counter interface {
+	// Add records an increment to the counter, incr must be non-negative.
+	Add(ctx context.Context, incr float64, attributes ...attribute.KeyValue)
+}
+
+// UpDownCounter is an instrument that records increasing or decreasing
+// values, such as: the count of active connections.
+type UpDownCounter interface {
+	// Add records a change to the counter.
+	Add(ctx context.Context, delta float64, attributes ...attribute.KeyValue)
+}
+
+// Histogram is an instrument that records a distribution of values, such as:
+// the duration of requests.
+type Histogram interface {
+	// Record records a value to the histogram.
+	Record(ctx context.Context, value float64, attributes ...attribute.KeyValue)
+}
+
+type InstrumentOption interface {
+	apply(o *instrumentOption)
+}
+
+type instrumentOption struct {
+	description string
+	unit        string
+	buckets     []float64 // buckets only works for Histogram.
+}
+
+func defaultInstrumentOption() *instrumentOption {
+	return &instrumentOption{
+		description: "",
+		unit:        "",
+		buckets:     nil,
+	}
+}
+
+type fnInstrumentOption func(opts *instrumentOption)
+
+func (fn fnInstrumentOption) apply(opts *instrumentOption) { fn(opts) }
+func newFnInstrumentOption(fn func(option *instrumentOption)) InstrumentOption {
+	return fnInstrumentOption(fn)
+}
+
+// WithDescription sets the description of the instrument.
+func WithDescription(description string) InstrumentOption {
+	return newFnInstrumentOption(func(option *instrumentOption) {
+		option.description = description
+	})
+}
+
+// WithUnit sets the unit of the instrument, such as: "ms", "By", "{request}".
+func WithUnit(unit string) InstrumentOption {
+	return newFnInstrumentOption(func(option *instrumentOption) {
+		option.unit = unit
+	})
+}
+
+// WithBuckets sets the explicit bucket boundaries of Histogram.
+func WithBuckets(buckets ...float64) InstrumentOption {
+	return newFnInstrumentOption(func(option *instrumentOption) {
+		option.buckets = buckets
+	})
+}
+
+func meter() metric.Meter {
+	return otel.Meter(tracerInstrumentationLibName,
+		metric.WithInstrumentationVersion(tracerInstrumentationVersion),
+	)
+}
+
+// NewCounter creates a Counter named name. The measurements recorded with a
+// context carrying a sampled span would carry the span as an exemplar.
+//
+// Instruments could be created before Setup, they start to work after Setup.
+func NewCounter(name string, opts ...InstrumentOption) Counter {
+	o := defaultInstrumentOption()
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	c, err := meter().Float64Counter(name,
+		metric.WithDescription(o.description),
+		metric.WithUnit(o.unit),
+	)
+	if err != nil {
+		fmt.Printf("[med/opentelemetry] WARNNING: create counter %s failed: %v\n", name, err)
+		c = noop.Float64Counter{}
+	}
+
+	return counter{c: c}
+}
+
+// NewUpDownCounter creates an UpDownCounter named name.
+func NewUpDownCounter(name string, opts ...InstrumentOption) UpDownCounter {
+	o := defaultInstrumentOption()
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	c, err := meter().Float64UpDownCounter(name,
+		metric.WithDescription(o.description),
+		metric.WithUnit(o.unit),
+	)
+	if err != nil {
+		fmt.Printf("[med/opentelemetry] WARNNING: create up-down counter %s failed: %v\n", name, err)
+		c = noop.Float64UpDownCounter{}
+	}
+
+	return upDownCounter{c: c}
+}
+
+// NewHistogram creates a Histogram named name. The measurements recorded with
+// a context carrying a sampled span would carry the span as an exemplar.
+func NewHistogram(name string, opts ...InstrumentOption) Histogram {
+	o := defaultInstrumentOption()
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	histogramOptions := []metric.Float64HistogramOption{
+		metric.WithDescription(o.description),
+		metric.WithUnit(o.unit),
+	}
+	if len(o.buckets) != 0 {
+		histogramOptions = append(histogramOptions, metric.WithExplicitBucketBoundaries(o.buckets...))
+	}
+
+	h, err := meter().Float64Histogram(name, histogramOptions...)
+	if err != nil {
+		fmt.Printf("[med/opentelemetry] WARNNING: create histogram %s failed: %v\n", name, err)
+		h = noop.Float64Histogram{}
+	}
+
+	return histogram{h: h}
+}
+
+type counter struct {
+	c metric.Float64Counter
+}
+
+func (c counter) Add(ctx context.Context, incr float64, attributes ...attribute.KeyValue) {
+	c.c.Add(ctx, incr, metric.WithAttributes(attributes...))
+}
+
+type upDownCounter struct {
+	c metric.Float64UpDownCounter
+}
+
+func (c upDownCounter) Add(ctx context.Context, delta float64, attributes ...attribute.KeyValue) {
+	c.c.Add(ctx, delta, metric.WithAttributes(attributes...))
+}
+
+type histogram struct {
+	h metric.Float64Histogram
+}
+
+func (h histogram) Record(ctx context.Context, value float64, attributes ...attribute.KeyValue) {
+	h.h.Record(ctx, value, metric.WithAttributes(attributes...))
+}
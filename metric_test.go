@@ -0,0 +1,124 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ExampleNewCounter() {
+	requests := tracing.NewCounter("app.requests",
+		tracing.WithDescription("the count of requests"),
+		tracing.WithUnit("{request}"),
+	)
+
+	requests.Add(context.Background(), 1, attribute.String("route", "/users"))
+}
+
+func ExampleNewHistogram() {
+	latency := tracing.NewHistogram("app.latency",
+		tracing.WithUnit("s"),
+		tracing.WithBuckets(0.01, 0.05, 0.1, 0.5, 1),
+	)
+
+	latency.Record(context.Background(), 0.042)
+}
+
+type metricTestSuite struct {
+	suite.Suite
+
+	reader         *sdkmetric.ManualReader
+	previousMeter  metric.MeterProvider
+	previousTracer trace.TracerProvider
+}
+
+func (s *metricTestSuite) SetupSuite() {
+	s.previousMeter = otel.GetMeterProvider()
+	s.previousTracer = otel.GetTracerProvider()
+
+	s.reader = sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(s.reader),
+		sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter),
+	))
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+}
+
+func (s *metricTestSuite) TearDownSuite() {
+	otel.SetMeterProvider(s.previousMeter)
+	otel.SetTracerProvider(s.previousTracer)
+}
+
+func (s *metricTestSuite) collect(name string) metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	s.Require().NoError(s.reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+
+	s.FailNow("metric not found", name)
+	return nil
+}
+
+func (s *metricTestSuite) Test_Counter() {
+	c := tracing.NewCounter("test.counter", tracing.WithUnit("{call}"))
+	c.Add(context.Background(), 1, attribute.String("k", "v"))
+	c.Add(context.Background(), 2, attribute.String("k", "v"))
+
+	sum, ok := s.collect("test.counter").(metricdata.Sum[float64])
+	s.Require().True(ok)
+	s.True(sum.IsMonotonic)
+	s.Require().Len(sum.DataPoints, 1)
+	s.Equal(float64(3), sum.DataPoints[0].Value)
+	s.Empty(sum.DataPoints[0].Exemplars)
+}
+
+func (s *metricTestSuite) Test_UpDownCounter() {
+	c := tracing.NewUpDownCounter("test.updown")
+	c.Add(context.Background(), 5)
+	c.Add(context.Background(), -2)
+
+	sum, ok := s.collect("test.updown").(metricdata.Sum[float64])
+	s.Require().True(ok)
+	s.False(sum.IsMonotonic)
+	s.Require().Len(sum.DataPoints, 1)
+	s.Equal(float64(3), sum.DataPoints[0].Value)
+}
+
+func (s *metricTestSuite) Test_Histogram_Exemplar() {
+	h := tracing.NewHistogram("test.histogram", tracing.WithBuckets(1, 10))
+
+	ctx, sp := tracing.StartSpan(context.Background(), "metric")
+	h.Record(ctx, 5)
+	sp.End()
+
+	hist, ok := s.collect("test.histogram").(metricdata.Histogram[float64])
+	s.Require().True(ok)
+	s.Require().Len(hist.DataPoints, 1)
+	dp := hist.DataPoints[0]
+	s.Equal([]float64{1, 10}, dp.Bounds)
+	s.Equal(uint64(1), dp.Count)
+	s.Require().Len(dp.Exemplars, 1)
+
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	s.Equal(traceID[:], dp.Exemplars[0].TraceID)
+}
+
+func Test_metric(t *testing.T) {
+	suite.Run(t, new(metricTestSuite))
+}
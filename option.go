@@ -1,8 +1,9 @@
 package tracing
 
 import (
-	"time"
+	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -11,18 +12,55 @@ type SpanStartOption interface {
 }
 
 type startSpanOption struct {
-	kind spanKind
+	kind    spanKind
+	newRoot bool
+
+	// traceOptions is reused by translateToTraceOptions to avoid allocating.
+	traceOptions []trace.SpanStartOption
 }
 
 func defaultSpanStartOption() *startSpanOption {
 	return &startSpanOption{
-		kind: SpanKindUnspecified,
+		kind:    SpanKindUnspecified,
+		newRoot: false,
+
+		traceOptions: make([]trace.SpanStartOption, 0, 2),
 	}
 }
 
-func (o *startSpanOption) translateToTraceOptions() []trace.SpanStartOption {
-	traceOptions := make([]trace.SpanStartOption, 0, 2)
-	traceOptions = append(traceOptions, trace.WithSpanKind(o.kind), trace.WithTimestamp(time.Now()))
+var _startSpanOptionPool = sync.Pool{
+	New: func() interface{} { return defaultSpanStartOption() },
+}
+
+// acquireSpanStartOption returns a startSpanOption with default values from
+// pool, it should be released by releaseSpanStartOption after use.
+func acquireSpanStartOption() *startSpanOption {
+	o := _startSpanOptionPool.Get().(*startSpanOption)
+	o.kind = SpanKindUnspecified
+	o.newRoot = false
+	return o
+}
+
+func releaseSpanStartOption(o *startSpanOption) {
+	for i := range o.traceOptions {
+		o.traceOptions[i] = nil
+	}
+	o.traceOptions = o.traceOptions[:0]
+	_startSpanOptionPool.Put(o)
+}
+
+// translateToTraceOptions translates startSpanOption to trace.SpanStartOption,
+// parent is the span context of the span which is in the context passed to StartSpan.
+// The returned slice is only valid before o is released.
+func (o *startSpanOption) translateToTraceOptions(parent trace.SpanContext) []trace.SpanStartOption {
+	traceOptions := append(o.traceOptions[:0], trace.WithSpanKind(o.kind))
+	if o.newRoot {
+		traceOptions = append(traceOptions, trace.WithNewRoot())
+		if parent.IsValid() {
+			traceOptions = append(traceOptions, trace.WithLinks(trace.Link{SpanContext: parent}))
+		}
+	}
+	o.traceOptions = traceOptions
 	return traceOptions
 }
 
@@ -33,10 +71,24 @@ func newFnStartSpanOption(fn func(option *startSpanOption)) SpanStartOption {
 	return fnStartSpanOption(fn)
 }
 
+// spanKindOption and newRootOption are not closures like other options, so
+// that passing them to StartSpan does not allocate.
+type spanKindOption spanKind
+
+func (k spanKindOption) apply(opts *startSpanOption) { opts.kind = spanKind(k) }
+
+type newRootOption struct{}
+
+func (newRootOption) apply(opts *startSpanOption) { opts.newRoot = true }
+
 func WithSpanKind(kind spanKind) SpanStartOption {
-	return newFnStartSpanOption(func(option *startSpanOption) {
-		option.kind = kind
-	})
+	return spanKindOption(kind)
+}
+
+// WithNewRoot starts a new root span instead of a child of the span in context,
+// and the new root span links to the span in context if there is one.
+func WithNewRoot() SpanStartOption {
+	return newRootOption{}
 }
 
 type SpanEventOption interface {
@@ -51,13 +103,17 @@ func defaultSpanEventOption() *spanEventOption {
 
 type spanEventOption struct {
 	withStackTrace bool
+	attributes     []attribute.KeyValue
 }
 
 func (o *spanEventOption) translateToEventOptions() []trace.EventOption {
-	eventOptions := make([]trace.EventOption, 0, 1)
+	eventOptions := make([]trace.EventOption, 0, 2)
 	if o.withStackTrace {
 		eventOptions = append(eventOptions, trace.WithStackTrace(true))
 	}
+	if len(o.attributes) != 0 {
+		eventOptions = append(eventOptions, trace.WithAttributes(o.attributes...))
+	}
 	return eventOptions
 }
 
@@ -73,3 +129,11 @@ func WithStackTrace() SpanEventOption {
 		option.withStackTrace = true
 	})
 }
+
+// WithEventAttributes adds attributes to the event, such as: the exception
+// event recorded by RecordError.
+func WithEventAttributes(attrs ...attribute.KeyValue) SpanEventOption {
+	return newFnSpanEventOption(func(option *spanEventOption) {
+		option.attributes = append(option.attributes, attrs...)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func Test_StartSpanOption(t *testing.T) {
@@ -12,6 +13,10 @@ func Test_StartSpanOption(t *testing.T) {
 	assert.Equal(t, SpanKindUnspecified, o.kind)
 	WithSpanKind(SpanKindServer).apply(o)
 	assert.Equal(t, SpanKindServer, o.kind)
+
+	assert.Equal(t, false, o.newRoot)
+	WithNewRoot().apply(o)
+	assert.Equal(t, true, o.newRoot)
 }
 
 func Test_SpanEventOption(t *testing.T) {
@@ -20,4 +25,9 @@ func Test_SpanEventOption(t *testing.T) {
 	assert.Equal(t, false, o.withStackTrace)
 	WithStackTrace().apply(o)
 	assert.Equal(t, true, o.withStackTrace)
+
+	assert.Empty(t, o.attributes)
+	WithEventAttributes(attribute.String("key", "value")).apply(o)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("key", "value")}, o.attributes)
+	assert.Len(t, o.translateToEventOptions(), 2)
 }
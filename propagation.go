@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"net/http"
 
 	"go.opentelemetry.io/otel/propagation"
 )
@@ -13,6 +14,15 @@ type TraceContextCarrier interface {
 	Set(key, value string)
 }
 
+// TraceContextKeysCarrier is an optional extension of TraceContextCarrier,
+// it's required by propagators which iterate keys of carrier, such as:
+// jaeger baggage (uberctx-*) or OT baggage (ot-baggage-*). Propagators see
+// no keys if the carrier doesn't implement it.
+type TraceContextKeysCarrier interface {
+	TraceContextCarrier
+	Keys() []string
+}
+
 // TraceContextPropagator is the type of the propagator to be used for
 // propagating the trace context across processes.
 type TraceContextPropagator interface {
@@ -28,9 +38,21 @@ type carrierAdapter struct {
 
 func (c carrierAdapter) Get(key string) string        { return c.carrier.Get(key) }
 func (c carrierAdapter) Set(key string, value string) { c.carrier.Set(key, value) }
-func (c carrierAdapter) Keys() []string               { return nil } // noop
 
-var _ TraceContextCarrier = (*mapCarrier)(nil)
+// Keys returns keys of carrier if it implements TraceContextKeysCarrier,
+// http.Header is supported as well since it's used as carrier directly.
+func (c carrierAdapter) Keys() []string {
+	switch carrier := c.carrier.(type) {
+	case TraceContextKeysCarrier:
+		return carrier.Keys()
+	case http.Header:
+		return HeaderCarrier(carrier).Keys()
+	}
+
+	return nil
+}
+
+var _ TraceContextKeysCarrier = (*mapCarrier)(nil)
 
 type mapCarrier map[string]string
 
@@ -54,6 +76,37 @@ func (m mapCarrier) Set(key, value string) {
 	m[key] = value
 }
 
+func (m mapCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+var _ TraceContextKeysCarrier = HeaderCarrier(nil)
+
+// HeaderCarrier adapts http.Header to TraceContextKeysCarrier.
+type HeaderCarrier http.Header
+
+func (h HeaderCarrier) Get(key string) string {
+	return http.Header(h).Get(key)
+}
+
+func (h HeaderCarrier) Set(key, value string) {
+	http.Header(h).Set(key, value)
+}
+
+func (h HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
 var (
 	_          TraceContextPropagator = (*defaultTraceContextPropagator)(nil)
 	propagator TraceContextPropagator = defaultTraceContextPropagator{}
@@ -73,6 +126,10 @@ func (d defaultTraceContextPropagator) Extract(ctx context.Context, carrier Trac
 	return propagation.TraceContext{}.Extract(ctx, carrierAdapter{carrier})
 }
 
+func (d defaultTraceContextPropagator) Fields() []string {
+	return propagation.TraceContext{}.Fields()
+}
+
 // GetPropagator returns the trace context propagator.
 func GetPropagator() TraceContextPropagator {
 	return propagator
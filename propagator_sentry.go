@@ -0,0 +1,242 @@
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	sentryTraceHeader   = "sentry-trace"
+	sentryBaggageHeader = "baggage"
+	sentryBaggagePrefix = "sentry-"
+)
+
+type SentryOption interface {
+	apply(o *sentryOption)
+}
+
+// sentryOption holds the dynamic sampling context (DSC) of sentry, it's
+// injected into baggage for traces started by this service.
+type sentryOption struct {
+	publicKey   string
+	environment string
+	release     string
+	sampleRate  string
+}
+
+type fnSentryOption func(o *sentryOption)
+
+func (fn fnSentryOption) apply(o *sentryOption) { fn(o) }
+func newFnSentryOption(fn func(o *sentryOption)) SentryOption {
+	return fnSentryOption(fn)
+}
+
+// WithSentryDSN sets the public key of sentry-public_key from dsn, such as:
+// https://<public_key>@o0.ingest.sentry.io/0.
+func WithSentryDSN(dsn string) SentryOption {
+	return newFnSentryOption(func(o *sentryOption) {
+		if u, err := url.Parse(dsn); err == nil && u.User != nil {
+			o.publicKey = u.User.Username()
+		}
+	})
+}
+
+// WithSentryEnvironment sets sentry-environment.
+func WithSentryEnvironment(env string) SentryOption {
+	return newFnSentryOption(func(o *sentryOption) {
+		o.environment = env
+	})
+}
+
+// WithSentryRelease sets sentry-release, such as: the version of service.
+func WithSentryRelease(release string) SentryOption {
+	return newFnSentryOption(func(o *sentryOption) {
+		o.release = release
+	})
+}
+
+// WithSentrySampleRate sets sentry-sample_rate, it should be the same as
+// sample ratio of Setup.
+func WithSentrySampleRate(rate float64) SentryOption {
+	return newFnSentryOption(func(o *sentryOption) {
+		o.sampleRate = strconv.FormatFloat(rate, 'f', -1, 64)
+	})
+}
+
+// SentryPropagator returns the propagator of sentry which is used by sentry
+// SDKs, such as: browser, python and node SDKs. It reads and writes
+// sentry-trace, and the sentry-* entries of baggage which are the dynamic
+// sampling context of sentry. Other entries of baggage are kept.
+//
+// The dynamic sampling context extracted from upstream is propagated as it
+// is, otherwise it's generated by opts.
+func SentryPropagator(opts ...SentryOption) TraceContextPropagator {
+	o := sentryOption{}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return textMapPropagator{TextMapPropagator: sentryTextMapPropagator{dsc: o}}
+}
+
+// sentryDSCKey is the key of sentry-* baggage entries in context, which are
+// extracted from upstream.
+type sentryDSCKey struct{}
+
+// sentryDSC is the dynamic sampling context of sentry, it's frozen once it
+// has been propagated.
+type sentryDSC struct {
+	traceID trace.TraceID
+	entries []string // entries are raw sentry-* baggage members, such as: sentry-release=v1.
+}
+
+// sentryTextMapPropagator propagates sentry-trace header which matches either
+//
+//	TRACE_ID - SPAN_ID
+//	[[:xdigit:]]{32}-[[:xdigit:]]{16}
+//
+// or
+//
+//	TRACE_ID - SPAN_ID - SAMPLED
+//	[[:xdigit:]]{32}-[[:xdigit:]]{16}-[01]
+//
+// and the sentry-* entries of baggage header.
+//
+// links:
+// - github.com/getsentry/sentry-go/tracing.go
+// - https://develop.sentry.dev/sdk/performance/dynamic-sampling-context/
+type sentryTextMapPropagator struct {
+	dsc sentryOption
+}
+
+func (p sentryTextMapPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	carrier.Set(sentryTraceHeader, sc.TraceID().String()+"-"+sc.SpanID().String()+"-"+sampled)
+
+	entries := p.entries(ctx, sc)
+	carrier.Set(sentryBaggageHeader, mergeSentryBaggage(carrier.Get(sentryBaggageHeader), entries))
+}
+
+// entries returns the sentry-* baggage entries of sc, the frozen entries from
+// upstream are used if they belong to the same trace.
+func (p sentryTextMapPropagator) entries(ctx context.Context, sc trace.SpanContext) []string {
+	if frozen, ok := ctx.Value(sentryDSCKey{}).(*sentryDSC); ok && frozen.traceID == sc.TraceID() {
+		return frozen.entries
+	}
+
+	entries := make([]string, 0, 6)
+	appendEntry := func(key, value string) {
+		if value != "" {
+			entries = append(entries, sentryBaggagePrefix+key+"="+escapeBaggageValue(value))
+		}
+	}
+	appendEntry("trace_id", sc.TraceID().String())
+	appendEntry("public_key", p.dsc.publicKey)
+	appendEntry("environment", p.dsc.environment)
+	appendEntry("release", p.dsc.release)
+	appendEntry("sample_rate", p.dsc.sampleRate)
+	appendEntry("sampled", strconv.FormatBool(sc.IsSampled()))
+
+	return entries
+}
+
+func (sentryTextMapPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, ok := parseSentryTrace(carrier.Get(sentryTraceHeader))
+	if !ok {
+		return ctx
+	}
+
+	if entries := sentryBaggageEntries(carrier.Get(sentryBaggageHeader)); len(entries) != 0 {
+		ctx = context.WithValue(ctx, sentryDSCKey{}, &sentryDSC{traceID: sc.TraceID(), entries: entries})
+	}
+
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func (sentryTextMapPropagator) Fields() []string {
+	return []string{sentryTraceHeader, sentryBaggageHeader}
+}
+
+// parseSentryTrace parses sentry-trace header into a remote span context. The
+// span is not sampled if the sampled flag is missing, since the sampling
+// decision is deferred to the receiver by sentry.
+func parseSentryTrace(value string) (trace.SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 && len(parts) != 3 {
+		return trace.SpanContext{}, false
+	}
+
+	traceID, err := trace.TraceIDFromHex(parts[0])
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(parts[1])
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	var flags trace.TraceFlags
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "1":
+			flags = trace.FlagsSampled
+		case "0":
+		default:
+			return trace.SpanContext{}, false
+		}
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}), true
+}
+
+// sentryBaggageEntries returns the sentry-* members of baggage.
+func sentryBaggageEntries(baggage string) []string {
+	var entries []string
+	for _, member := range strings.Split(baggage, ",") {
+		member = strings.TrimSpace(member)
+		if strings.HasPrefix(member, sentryBaggagePrefix) {
+			entries = append(entries, member)
+		}
+	}
+
+	return entries
+}
+
+// mergeSentryBaggage replaces the sentry-* members of baggage with entries,
+// and keeps other members.
+func mergeSentryBaggage(baggage string, entries []string) string {
+	members := make([]string, 0, 4+len(entries))
+	for _, member := range strings.Split(baggage, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" || strings.HasPrefix(member, sentryBaggagePrefix) {
+			continue
+		}
+		members = append(members, member)
+	}
+	members = append(members, entries...)
+
+	return strings.Join(members, ",")
+}
+
+// escapeBaggageValue percent-encodes value as the W3C baggage specification.
+func escapeBaggageValue(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// fieldsPropagator is implemented by propagators which know the keys they
+// read and write, it's used to report Fields to otel.
+type fieldsPropagator interface {
+	Fields() []string
+}
+
+// W3CPropagator returns the propagator of W3C trace context, it reads and
+// writes traceparent and tracestate. It's the default propagator.
+func W3CPropagator() TraceContextPropagator {
+	return defaultTraceContextPropagator{}
+}
+
+// B3Propagator returns the propagator of B3 which is used by zipkin and
+// istio. It extracts both single header (b3) and multiple headers (x-b3-*),
+// and injects both of them.
+func B3Propagator() TraceContextPropagator {
+	return textMapPropagator{
+		TextMapPropagator: b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader | b3.B3SingleHeader)),
+	}
+}
+
+// JaegerPropagator returns the propagator of jaeger, it reads and writes
+// uber-trace-id.
+func JaegerPropagator() TraceContextPropagator {
+	return textMapPropagator{TextMapPropagator: jaeger.Jaeger{}}
+}
+
+// NewCompositePropagator creates a propagator which injects into all formats
+// of propagators, and extracts from the first format present in carrier by
+// the order of propagators.
+func NewCompositePropagator(propagators ...TraceContextPropagator) TraceContextPropagator {
+	if len(propagators) == 1 {
+		return propagators[0]
+	}
+
+	return compositePropagator(propagators)
+}
+
+// FromTextMapPropagator adapts propagation.TextMapPropagator of otel, such as:
+// propagators of go.opentelemetry.io/contrib/propagators, to
+// TraceContextPropagator. Keys of carrier are visible to p only if carrier
+// implements TraceContextKeysCarrier or is http.Header.
+func FromTextMapPropagator(p propagation.TextMapPropagator) TraceContextPropagator {
+	return textMapPropagator{TextMapPropagator: p}
+}
+
+// textMapPropagator adapts propagation.TextMapPropagator to TraceContextPropagator.
+type textMapPropagator struct {
+	propagation.TextMapPropagator
+}
+
+func (p textMapPropagator) Inject(ctx context.Context, carrier TraceContextCarrier) {
+	p.TextMapPropagator.Inject(ctx, carrierAdapter{carrier})
+}
+
+func (p textMapPropagator) Extract(ctx context.Context, carrier TraceContextCarrier) context.Context {
+	return p.TextMapPropagator.Extract(ctx, carrierAdapter{carrier})
+}
+
+type compositePropagator []TraceContextPropagator
+
+func (c compositePropagator) Inject(ctx context.Context, carrier TraceContextCarrier) {
+	for _, p := range c {
+		p.Inject(ctx, carrier)
+	}
+}
+
+// Extract runs propagators in reverse order, so that the span context of the
+// first format present overrides others, and values other than span context,
+// such as: sentry baggage, are kept in context.
+func (c compositePropagator) Extract(ctx context.Context, carrier TraceContextCarrier) context.Context {
+	for i := len(c) - 1; i >= 0; i-- {
+		ctx = c[i].Extract(ctx, carrier)
+	}
+
+	return ctx
+}
+
+func (c compositePropagator) Fields() []string {
+	fields := make([]string, 0, 2*len(c))
+	for _, p := range c {
+		if fp, ok := p.(fieldsPropagator); ok {
+			fields = append(fields, fp.Fields()...)
+		}
+	}
+
+	return fields
+}
+
+// globalPropagator is registered into otel in Setup, so that other
+// instrumentation, such as: otelhttp, uses the propagator of GetPropagator.
+type globalPropagator struct{}
+
+var _ propagation.TextMapPropagator = globalPropagator{}
+
+func (globalPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	GetPropagator().Inject(ctx, carrier)
+}
+
+func (globalPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return GetPropagator().Extract(ctx, carrier)
+}
+
+func (globalPropagator) Fields() []string {
+	if fp, ok := GetPropagator().(fieldsPropagator); ok {
+		return fp.Fields()
+	}
+
+	return nil
+}
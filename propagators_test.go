@@ -0,0 +1,219 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func ExampleWithPropagators() {
+	shutdown, err := tracing.Setup(
+		tracing.WithServerName("propagators"),
+		// extract from traceparent first, then b3, uber-trace-id and sentry-trace.
+		tracing.WithPropagators(
+			tracing.W3CPropagator(),
+			tracing.B3Propagator(),
+			tracing.JaegerPropagator(),
+			tracing.SentryPropagator(),
+		),
+	)
+	_, _ = shutdown, err
+}
+
+var (
+	testTraceID = trace.TraceID{0x99, 0x4f, 0x3d, 0x6c, 0xeb, 0xfc, 0x1b, 0x8f, 0x19, 0xc5, 0x2a, 0x8c, 0x68, 0x7a, 0xb5, 0xf3}
+	testSpanID  = trace.SpanID{0x0c, 0x77, 0xd1, 0x21, 0xfe, 0xe1, 0xc0, 0x2a}
+)
+
+func contextWithTestSpan() context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     testSpanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func Test_Propagators_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		propagator tracing.TraceContextPropagator
+		header     string
+	}{
+		{name: "w3c", propagator: tracing.W3CPropagator(), header: "traceparent"},
+		{name: "b3", propagator: tracing.B3Propagator(), header: "x-b3-traceid"},
+		{name: "jaeger", propagator: tracing.JaegerPropagator(), header: "uber-trace-id"},
+		{name: "sentry", propagator: tracing.SentryPropagator(), header: "sentry-trace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carrier := tracing.NewMapCarrier()
+			tt.propagator.Inject(contextWithTestSpan(), carrier)
+			assert.NotEmpty(t, carrier.Get(tt.header))
+
+			sc := trace.SpanContextFromContext(tt.propagator.Extract(context.Background(), carrier))
+			assert.True(t, sc.IsRemote())
+			assert.Equal(t, testTraceID, sc.TraceID())
+			assert.Equal(t, testSpanID, sc.SpanID())
+			assert.True(t, sc.IsSampled())
+		})
+	}
+}
+
+func Test_SentryPropagator_Extract(t *testing.T) {
+	tests := []struct {
+		name        string
+		sentryTrace string
+		valid       bool
+		sampled     bool
+	}{
+		{name: "sampled", sentryTrace: "994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-1", valid: true, sampled: true},
+		{name: "not sampled", sentryTrace: "994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-0", valid: true},
+		{name: "deferred", sentryTrace: "994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a", valid: true},
+		{name: "empty", sentryTrace: ""},
+		{name: "missing span", sentryTrace: "00-994f3d6cebfc1b8f19c52a8c687ab5f3"},
+		{name: "invalid sampled", sentryTrace: "994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-x"},
+		{name: "traceparent", sentryTrace: "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carrier := tracing.NewMapCarrier()
+			carrier.Set("sentry-trace", tt.sentryTrace)
+
+			sc := trace.SpanContextFromContext(tracing.SentryPropagator().Extract(context.Background(), carrier))
+			assert.Equal(t, tt.valid, sc.IsValid())
+			assert.Equal(t, tt.sampled, sc.IsSampled())
+		})
+	}
+}
+
+func Test_CompositePropagator(t *testing.T) {
+	p := tracing.NewCompositePropagator(tracing.W3CPropagator(), tracing.B3Propagator(), tracing.SentryPropagator())
+
+	// inject into all formats.
+	carrier := tracing.NewMapCarrier()
+	p.Inject(contextWithTestSpan(), carrier)
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+	assert.NotEmpty(t, carrier.Get("b3"))
+	assert.NotEmpty(t, carrier.Get("sentry-trace"))
+
+	// extract from the first format present.
+	carrier = tracing.NewMapCarrier()
+	carrier.Set("sentry-trace", "11111111111111111111111111111111-2222222222222222-1")
+	carrier.Set("x-b3-traceid", testTraceID.String())
+	carrier.Set("x-b3-spanid", testSpanID.String())
+	carrier.Set("x-b3-sampled", "1")
+	sc := trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+	require.True(t, sc.IsValid())
+	assert.Equal(t, testTraceID, sc.TraceID())
+
+	carrier = tracing.NewMapCarrier()
+	carrier.Set("sentry-trace", "11111111111111111111111111111111-2222222222222222-1")
+	sc = trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+	assert.Equal(t, "11111111111111111111111111111111", sc.TraceID().String())
+
+	// nothing present.
+	sc = trace.SpanContextFromContext(p.Extract(context.Background(), tracing.NewMapCarrier()))
+	assert.False(t, sc.IsValid())
+}
+
+func Test_SentryPropagator_Baggage(t *testing.T) {
+	p := tracing.SentryPropagator(
+		tracing.WithSentryDSN("https://public@o0.ingest.sentry.io/1"),
+		tracing.WithSentryEnvironment("prod"),
+		tracing.WithSentryRelease("v1.0.0"),
+		tracing.WithSentrySampleRate(0.2),
+	)
+
+	// generate entries and keep other members of baggage.
+	carrier := tracing.NewMapCarrier()
+	carrier.Set("baggage", "userId=alice,sentry-trace_id=stale")
+	p.Inject(contextWithTestSpan(), carrier)
+	assert.Equal(t, testTraceID.String()+"-"+testSpanID.String()+"-1", carrier.Get("sentry-trace"))
+	assert.Equal(t, "userId=alice,"+
+		"sentry-trace_id="+testTraceID.String()+","+
+		"sentry-public_key=public,"+
+		"sentry-environment=prod,"+
+		"sentry-release=v1.0.0,"+
+		"sentry-sample_rate=0.2,"+
+		"sentry-sampled=true", carrier.Get("baggage"))
+
+	// the entries from upstream are propagated as it is.
+	upstream := tracing.NewMapCarrier()
+	upstream.Set("sentry-trace", testTraceID.String()+"-"+testSpanID.String()+"-1")
+	upstream.Set("baggage", "other=1,sentry-trace_id="+testTraceID.String()+",sentry-release=frontend%401.0")
+	ctx := p.Extract(context.Background(), upstream)
+
+	ctx, sp := noop.NewTracerProvider().Tracer("").Start(ctx, "child")
+	defer sp.End()
+	downstream := tracing.NewMapCarrier()
+	p.Inject(ctx, downstream)
+	assert.Equal(t, "sentry-trace_id="+testTraceID.String()+",sentry-release=frontend%401.0", downstream.Get("baggage"))
+}
+
+// prefixPropagator extracts all keys with prefix of carrier into context, it
+// works only if keys of carrier are visible.
+type prefixPropagator struct{}
+
+type prefixKey struct{}
+
+func (prefixPropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+func (prefixPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	values := make(map[string]string)
+	for _, key := range carrier.Keys() {
+		if strings.HasPrefix(strings.ToLower(key), "uberctx-") {
+			values[strings.ToLower(key)] = carrier.Get(key)
+		}
+	}
+
+	return context.WithValue(ctx, prefixKey{}, values)
+}
+
+func (prefixPropagator) Fields() []string { return nil }
+
+// getOnlyCarrier is a user-defined carrier without Keys.
+type getOnlyCarrier map[string]string
+
+func (c getOnlyCarrier) Get(key string) string { return c[key] }
+func (c getOnlyCarrier) Set(key, value string) { c[key] = value }
+
+func Test_TraceContextKeysCarrier(t *testing.T) {
+	p := tracing.FromTextMapPropagator(prefixPropagator{})
+
+	mc := tracing.NewMapCarrier()
+	mc.Set("uberctx-user", "alice")
+	mc.Set("other", "ignored")
+
+	h := http.Header{}
+	h.Set("Uberctx-User", "alice")
+	h.Set("Other", "ignored")
+
+	tests := []struct {
+		name    string
+		carrier tracing.TraceContextCarrier
+		want    map[string]string
+	}{
+		{name: "map", carrier: mc, want: map[string]string{"uberctx-user": "alice"}},
+		{name: "http.Header", carrier: h, want: map[string]string{"uberctx-user": "alice"}},
+		{name: "HeaderCarrier", carrier: tracing.HeaderCarrier(h), want: map[string]string{"uberctx-user": "alice"}},
+		{name: "without keys", carrier: getOnlyCarrier{"uberctx-user": "alice"}, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := p.Extract(context.Background(), tt.carrier)
+			assert.Equal(t, tt.want, ctx.Value(prefixKey{}))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDAttributeKey is the attribute key of request ID which is recorded
+// on server span if upstream doesn't propagate trace context.
+const requestIDAttributeKey = attribute.Key("request.id")
+
+// defaultRequestIDHeaders are the headers of legacy correlation IDs, they're
+// read in order.
+var defaultRequestIDHeaders = []string{"X-Request-ID", "X-Correlation-ID", "X-B3-TraceId"}
+
+type RequestIDOption interface {
+	apply(e *RequestIDExtractor)
+}
+
+type fnRequestIDOption func(e *RequestIDExtractor)
+
+func (fn fnRequestIDOption) apply(e *RequestIDExtractor) { fn(e) }
+func newFnRequestIDOption(fn func(e *RequestIDExtractor)) RequestIDOption {
+	return fnRequestIDOption(fn)
+}
+
+// WithRequestIDHeaders sets the headers to read request ID from, the first
+// non-empty one is used. Default: X-Request-ID, X-Correlation-ID, X-B3-TraceId.
+func WithRequestIDHeaders(headers ...string) RequestIDOption {
+	return newFnRequestIDOption(func(e *RequestIDExtractor) {
+		if len(headers) != 0 {
+			e.headers = headers
+		}
+	})
+}
+
+// WithRequestIDResponseHeader sets the header of response to echo back the
+// request ID. Default: X-Request-ID.
+func WithRequestIDResponseHeader(header string) RequestIDOption {
+	return newFnRequestIDOption(func(e *RequestIDExtractor) {
+		if header != "" {
+			e.responseHeader = header
+		}
+	})
+}
+
+// WithDeterministicTraceID derives the trace ID of server span from request
+// ID if upstream doesn't propagate trace context, so that logs keyed by
+// request ID join up with traces. A request ID which is already a trace ID,
+// such as: X-B3-TraceId or UUID, is used as it is, others are hashed.
+//
+// Only the server span uses the derived trace ID, other root spans started
+// from its context, such as: WithNewRoot or Go, get random trace IDs. The
+// request ID of an untrusted request of InboundPolicy is not used either.
+//
+// NOTICE: it only works with the TracerProvider created by Setup.
+func WithDeterministicTraceID() RequestIDOption {
+	return newFnRequestIDOption(func(e *RequestIDExtractor) {
+		e.deterministicTraceID = true
+	})
+}
+
+// RequestIDExtractor bridges legacy correlation IDs, such as: X-Request-ID,
+// into traces. It's used by inbound contrib packages after the propagator:
+//
+//	ctx = GetPropagator().Extract(ctx, carrier)
+//	ctx = extractor.Extract(ctx, carrier)
+//	ctx, sp := StartSpan(ctx, "server", WithSpanKind(SpanKindServer))
+//	sp.SetAttributes(extractor.Attributes(ctx)...)
+//	extractor.Inject(ctx, responseCarrier)
+type RequestIDExtractor struct {
+	headers              []string
+	responseHeader       string
+	deterministicTraceID bool
+}
+
+// NewRequestIDExtractor creates a RequestIDExtractor.
+func NewRequestIDExtractor(opts ...RequestIDOption) *RequestIDExtractor {
+	e := &RequestIDExtractor{
+		headers:              defaultRequestIDHeaders,
+		responseHeader:       "X-Request-ID",
+		deterministicTraceID: false,
+	}
+	for _, opt := range opts {
+		opt.apply(e)
+	}
+
+	return e
+}
+
+// requestIDKey is the key of requestID in context.
+type requestIDKey struct{}
+
+type requestID struct {
+	value string
+	// propagated is true if upstream propagates trace context besides request
+	// ID, the request ID is not recorded in this case.
+	propagated bool
+}
+
+// deterministicTraceIDKey is the key of *deterministicTraceID in context,
+// it's used by idGenerator.
+type deterministicTraceIDKey struct{}
+
+// deterministicTraceID is the trace ID derived from request ID. It's used by
+// the first root span started from the context only, which is the server span.
+type deterministicTraceID struct {
+	traceID trace.TraceID
+	used    atomic.Bool
+}
+
+// take returns the trace ID if it has never been taken.
+func (d *deterministicTraceID) take() (trace.TraceID, bool) {
+	if !d.used.CompareAndSwap(false, true) {
+		return trace.TraceID{}, false
+	}
+
+	return d.traceID, true
+}
+
+// Extract reads request ID from carrier into ctx, ctx should have been
+// extracted by propagator or InboundPolicy. ctx is returned as it is if no
+// request ID is found.
+func (e *RequestIDExtractor) Extract(ctx context.Context, carrier TraceContextCarrier) context.Context {
+	var value string
+	for _, header := range e.headers {
+		if value = strings.TrimSpace(carrier.Get(header)); value != "" {
+			break
+		}
+	}
+	if value == "" {
+		return ctx
+	}
+
+	propagated := trace.SpanContextFromContext(ctx).IsValid()
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID{value: value, propagated: propagated})
+	if e.deterministicTraceID && !propagated && !untrustedInbound(ctx) {
+		ctx = context.WithValue(ctx, deterministicTraceIDKey{},
+			&deterministicTraceID{traceID: traceIDFromRequestID(value)})
+	}
+
+	return ctx
+}
+
+// Attributes returns the attributes of request ID for server span, it's
+// empty if no request ID is extracted or upstream propagates trace context.
+func (e *RequestIDExtractor) Attributes(ctx context.Context) []attribute.KeyValue {
+	rid, ok := ctx.Value(requestIDKey{}).(requestID)
+	if !ok || rid.propagated {
+		return nil
+	}
+
+	return []attribute.KeyValue{requestIDAttributeKey.String(rid.value)}
+}
+
+// Inject echoes back the chosen ID into carrier of response, it's the request
+// ID if present, otherwise the trace ID of span in ctx.
+func (e *RequestIDExtractor) Inject(ctx context.Context, carrier TraceContextCarrier) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		carrier.Set(e.responseHeader, id)
+		return
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		carrier.Set(e.responseHeader, sc.TraceID().String())
+	}
+}
+
+// RequestIDFromContext returns the request ID extracted by
+// RequestIDExtractor, it's empty if there is no request ID.
+func RequestIDFromContext(ctx context.Context) string {
+	rid, _ := ctx.Value(requestIDKey{}).(requestID)
+	return rid.value
+}
+
+// traceIDFromRequestID derives trace ID from request ID. It's used directly
+// if it's a 128-bit or 64-bit hex, such as: UUID or X-B3-TraceId, otherwise
+// the first 16 bytes of its sha256 are used.
+func traceIDFromRequestID(value string) trace.TraceID {
+	hex := strings.ToLower(strings.ReplaceAll(value, "-", ""))
+	if len(hex) == 16 {
+		hex = strings.Repeat("0", 16) + hex
+	}
+	if tid, err := trace.TraceIDFromHex(hex); err == nil {
+		return tid
+	}
+
+	var tid trace.TraceID
+	sum := sha256.Sum256([]byte(value))
+	copy(tid[:], sum[:len(tid)])
+	return tid
+}
+
+var _ sdktrace.IDGenerator = (*idGenerator)(nil)
+
+// idGenerator generates random IDs as the default one of sdk, except that
+// the trace ID of the first root span is derived from request ID if it's
+// present in context.
+type idGenerator struct {
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+func newIDGenerator() *idGenerator {
+	return &idGenerator{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (g *idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var (
+		tid trace.TraceID
+		ok  bool
+	)
+	if d, _ := ctx.Value(deterministicTraceIDKey{}).(*deterministicTraceID); d != nil {
+		tid, ok = d.take()
+	}
+	if !ok || !tid.IsValid() {
+		for {
+			binary.BigEndian.PutUint64(tid[:8], g.random.Uint64())
+			binary.BigEndian.PutUint64(tid[8:], g.random.Uint64())
+			if tid.IsValid() {
+				break
+			}
+		}
+	}
+
+	return tid, g.newSpanID()
+}
+
+func (g *idGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.newSpanID()
+}
+
+func (g *idGenerator) newSpanID() (sid trace.SpanID) {
+	for !sid.IsValid() {
+		binary.BigEndian.PutUint64(sid[:], g.random.Uint64())
+	}
+
+	return sid
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_traceIDFromRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{name: "uuid", requestID: "4BF92F35-77B3-4DA6-A3CE-929D0E0E4736", want: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "b3 64-bit", requestID: "a3ce929d0e0e4736", want: "0000000000000000a3ce929d0e0e4736"},
+		{name: "hashed", requestID: "req-1", want: traceIDFromRequestID("req-1").String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tid := traceIDFromRequestID(tt.requestID)
+			assert.True(t, tid.IsValid())
+			assert.Equal(t, tt.want, tid.String())
+		})
+	}
+	assert.NotEqual(t, traceIDFromRequestID("req-1"), traceIDFromRequestID("req-2"))
+}
+
+func Test_RequestIDExtractor(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithIDGenerator(newIDGenerator())))
+	defer otel.SetTracerProvider(previous)
+
+	extractor := NewRequestIDExtractor(WithDeterministicTraceID())
+
+	t.Run("without trace context", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Correlation-ID", "req-1")
+
+		ctx := extractor.Extract(context.Background(), header)
+		assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+		assert.Equal(t, "request.id", string(extractor.Attributes(ctx)[0].Key))
+
+		ctx, sp := StartSpan(ctx, "server", WithSpanKind(SpanKindServer))
+		defer sp.End()
+		assert.Equal(t, traceIDFromRequestID("req-1").String(), sp.SpanContext().TraceID)
+
+		response := http.Header{}
+		extractor.Inject(ctx, response)
+		assert.Equal(t, "req-1", response.Get("X-Request-ID"))
+	})
+
+	t.Run("with trace context", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Request-ID", "req-1")
+		header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		ctx := W3CPropagator().Extract(context.Background(), header)
+		ctx = extractor.Extract(ctx, header)
+		assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+		assert.Empty(t, extractor.Attributes(ctx))
+
+		_, sp := StartSpan(ctx, "server", WithSpanKind(SpanKindServer))
+		defer sp.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sp.SpanContext().TraceID)
+	})
+
+	t.Run("only server span", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Request-ID", "req-1")
+
+		ctx, sp := StartSpan(extractor.Extract(context.Background(), header), "server",
+			WithSpanKind(SpanKindServer))
+		defer sp.End()
+		assert.Equal(t, traceIDFromRequestID("req-1").String(), sp.SpanContext().TraceID)
+
+		// later roots started from the request get their own trace IDs.
+		_, bg := StartSpan(Detach(ctx), "background", WithNewRoot())
+		defer bg.End()
+		assert.NotEqual(t, sp.SpanContext().TraceID, bg.SpanContext().TraceID)
+
+		done := make(chan string, 1)
+		Go(ctx, "async", func(ctx context.Context) error {
+			done <- SpanFromContext(ctx).SpanContext().TraceID
+			return nil
+		}, WithNewRoot())
+		assert.NotEqual(t, sp.SpanContext().TraceID, <-done)
+	})
+
+	t.Run("untrusted inbound", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Request-ID", "req-1")
+		header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		ctx, opt := NewInboundPolicy().Extract(context.Background(), InboundRequest{
+			RemoteAddr: "203.0.113.1:443",
+			Carrier:    header,
+		})
+		ctx = extractor.Extract(ctx, header)
+		assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+
+		_, sp := StartSpan(ctx, "server", WithSpanKind(SpanKindServer), opt)
+		defer sp.End()
+		assert.NotEqual(t, traceIDFromRequestID("req-1").String(), sp.SpanContext().TraceID)
+		assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", sp.SpanContext().TraceID)
+	})
+
+	t.Run("without request id", func(t *testing.T) {
+		ctx := extractor.Extract(context.Background(), http.Header{})
+		assert.Empty(t, RequestIDFromContext(ctx))
+		assert.Empty(t, extractor.Attributes(ctx))
+
+		ctx, sp := StartSpan(ctx, "server", WithSpanKind(SpanKindServer))
+		defer sp.End()
+		require.True(t, trace.SpanContextFromContext(ctx).IsValid())
+
+		// the trace ID is echoed back instead.
+		response := http.Header{}
+		extractor.Inject(ctx, response)
+		assert.Equal(t, sp.SpanContext().TraceID, response.Get("X-Request-ID"))
+	})
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error returned by Run and RunValue if fn panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine where panic happened.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+type RunOption interface {
+	apply(o *runOption)
+}
+
+type runOption struct {
+	startOptions []SpanStartOption
+	repanic      bool
+}
+
+func defaultRunOption() *runOption {
+	return &runOption{
+		startOptions: nil,
+		repanic:      false,
+	}
+}
+
+type fnRunOption func(opts *runOption)
+
+func (fn fnRunOption) apply(opts *runOption) { fn(opts) }
+func newFnRunOption(fn func(option *runOption)) RunOption {
+	return fnRunOption(fn)
+}
+
+// WithStartOptions specifies options to start the span wrapping fn.
+func WithStartOptions(opts ...SpanStartOption) RunOption {
+	return newFnRunOption(func(option *runOption) {
+		option.startOptions = append(option.startOptions, opts...)
+	})
+}
+
+// WithRepanic re-panics after the panic has been recorded and the span
+// has been ended, otherwise the panic is converted into a *PanicError.
+func WithRepanic() RunOption {
+	return newFnRunOption(func(option *runOption) {
+		option.repanic = true
+	})
+}
+
+// Run wraps fn in a span named name, and the context passed to fn carries the
+// span. The status of the span is set to Error if fn returns an error, panic in
+// fn would be recovered and recorded with stack trace, and then returned as a
+// *PanicError unless WithRepanic is specified. The span is always ended
+// before Run returns.
+func Run(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...RunOption) error {
+	_, err := RunValue(ctx, name, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	}, opts...)
+
+	return err
+}
+
+// RunValue is the same as Run, but fn could return a value.
+func RunValue(
+	ctx context.Context,
+	name string,
+	fn func(ctx context.Context) (interface{}, error),
+	opts ...RunOption,
+) (v interface{}, err error) {
+	o := defaultRunOption()
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	ctx, sp := StartSpan(ctx, name, o.startOptions...)
+	defer func() {
+		r := recover()
+		if r == nil {
+			sp.EndWithError(&err)
+			return
+		}
+
+		err = &PanicError{Value: r, Stack: debug.Stack()}
+		sp.RecordError(err, WithStackTrace())
+		sp.SetStatus(Error, err.Error())
+		sp.End()
+
+		if o.repanic {
+			panic(r)
+		}
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,159 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ExampleRun() {
+	err := tracing.Run(context.Background(), "example", func(ctx context.Context) error {
+		// do something with ctx which carries the span.
+		return nil
+	})
+
+	_ = err
+}
+
+func ExampleRunValue() {
+	v, err := tracing.RunValue(context.Background(), "example", func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	}, tracing.WithStartOptions(tracing.WithSpanKind(tracing.SpanKindInternal)))
+
+	_, _ = v, err
+}
+
+type runTestSuite struct {
+	suite.Suite
+
+	recorder *tracetest.SpanRecorder
+	previous trace.TracerProvider
+}
+
+func (s *runTestSuite) SetupSuite() {
+	s.previous = otel.GetTracerProvider()
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+}
+
+func (s *runTestSuite) TearDownSuite() {
+	otel.SetTracerProvider(s.previous)
+}
+
+func (s *runTestSuite) lastEnded() sdktrace.ReadOnlySpan {
+	spans := s.recorder.Ended()
+	require.NotEmpty(s.T(), spans)
+	return spans[len(spans)-1]
+}
+
+func (s *runTestSuite) Test_Run() {
+	t := s.T()
+
+	err := tracing.Run(context.Background(), "ok", func(ctx context.Context) error {
+		assert.True(t, tracing.TraceContextFromContext(ctx).IsValid())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", s.lastEnded().Name())
+	assert.Equal(t, codes.Unset, s.lastEnded().Status().Code)
+
+	errFailed := errors.New("failed")
+	err = tracing.Run(context.Background(), "failed", func(ctx context.Context) error {
+		return errFailed
+	})
+	assert.Equal(t, errFailed, err)
+	assert.Equal(t, codes.Error, s.lastEnded().Status().Code)
+	assert.Equal(t, "failed", s.lastEnded().Status().Description)
+	require.Len(t, s.lastEnded().Events(), 1)
+	assert.Equal(t, "exception", s.lastEnded().Events()[0].Name)
+}
+
+func (s *runTestSuite) Test_RunValue() {
+	t := s.T()
+
+	v, err := tracing.RunValue(context.Background(), "value", func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	}, tracing.WithStartOptions(tracing.WithSpanKind(tracing.SpanKindClient)))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, trace.SpanKindClient, s.lastEnded().SpanKind())
+}
+
+func (s *runTestSuite) Test_Run_panic() {
+	t := s.T()
+
+	err := tracing.Run(context.Background(), "panic", func(ctx context.Context) error {
+		panic("boom")
+	})
+	var pe *tracing.PanicError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "boom", pe.Value)
+	assert.NotEmpty(t, pe.Stack)
+	assert.Equal(t, "panic: boom", err.Error())
+
+	sp := s.lastEnded()
+	assert.Equal(t, "panic", sp.Name())
+	assert.Equal(t, codes.Error, sp.Status().Code)
+	require.Len(t, sp.Events(), 1)
+	hasStack := false
+	for _, attr := range sp.Events()[0].Attributes {
+		if attr.Key == "exception.stacktrace" {
+			hasStack = true
+		}
+	}
+	assert.True(t, hasStack)
+
+	assert.PanicsWithValue(t, "boom again", func() {
+		_ = tracing.Run(context.Background(), "repanic", func(ctx context.Context) error {
+			panic("boom again")
+		}, tracing.WithRepanic())
+	})
+	assert.Equal(t, "repanic", s.lastEnded().Name())
+	assert.Equal(t, codes.Error, s.lastEnded().Status().Code)
+}
+
+func (s *runTestSuite) Test_EndWithError() {
+	t := s.T()
+
+	do := func(ctx context.Context, failed bool) (err error) {
+		_, sp := tracing.StartSpan(ctx, "do")
+		defer sp.EndWithError(&err)
+
+		if failed {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	assert.NoError(t, do(context.Background(), false))
+	assert.Equal(t, codes.Unset, s.lastEnded().Status().Code)
+	assert.Error(t, do(context.Background(), true))
+	assert.Equal(t, codes.Error, s.lastEnded().Status().Code)
+
+	// Error status set inside is kept even if no error is returned.
+	degraded := func(ctx context.Context) (err error) {
+		_, sp := tracing.StartSpan(ctx, "degraded")
+		defer sp.EndWithError(&err)
+
+		sp.SetStatus(tracing.Error, "fallback")
+		return nil
+	}
+	assert.NoError(t, degraded(context.Background()))
+	assert.Equal(t, codes.Error, s.lastEnded().Status().Code)
+	assert.Equal(t, "fallback", s.lastEnded().Status().Description)
+}
+
+func Test_runSuite(t *testing.T) {
+	suite.Run(t, new(runTestSuite))
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	runtimemetrics "runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// _metricsHandler holds the http.HandlerFunc which exposes metrics in
+// prometheus text format, it's only set while no metric exporter is configured.
+var _metricsHandler atomic.Value
+
+// newPrometheusReader creates a sdkmetric.Reader which could be scraped by
+// prometheus, metrics would be exposed by MetricsHandler.
+func newPrometheusReader() (sdkmetric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	reader, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "newPrometheusReader failed")
+	}
+
+	return reader, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// startRuntimeMetrics starts collecting go runtime (goroutines, GC, heap) and
+// process CPU stats into provider, runtime stats are read at most once per
+// interval. The returned stop function stops recording GC pauses.
+func startRuntimeMetrics(provider metric.MeterProvider, interval time.Duration) (stop func(), err error) {
+	if err = runtime.Start(
+		runtime.WithMeterProvider(provider),
+		runtime.WithMinimumReadMemStatsInterval(interval),
+	); err != nil {
+		return nil, errors.Wrap(err, "start runtime metrics")
+	}
+
+	if err = startProcessCPUMetrics(provider); err != nil {
+		return nil, errors.Wrap(err, "start process cpu metrics")
+	}
+
+	if stop, err = startGCPauseMetrics(provider, interval); err != nil {
+		return nil, errors.Wrap(err, "start gc pause metrics")
+	}
+
+	return stop, nil
+}
+
+// cpuSecondsMetric is the go runtime's estimation of the CPU time spent by
+// the process, it's read from runtime/metrics without any extra dependency.
+const cpuSecondsMetric = "/cpu/classes/total:cpu-seconds"
+
+// startProcessCPUMetrics reports the CPU time of current process as
+// process.cpu.time.
+func startProcessCPUMetrics(provider metric.MeterProvider) error {
+	m := provider.Meter(tracerInstrumentationLibName,
+		metric.WithInstrumentationVersion(tracerInstrumentationVersion),
+	)
+
+	_, err := m.Float64ObservableCounter("process.cpu.time",
+		metric.WithUnit("s"),
+		metric.WithDescription("Total CPU seconds spent by the process, estimated by the go runtime."),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			samples := []runtimemetrics.Sample{{Name: cpuSecondsMetric}}
+			runtimemetrics.Read(samples)
+			if samples[0].Value.Kind() == runtimemetrics.KindFloat64 {
+				o.Observe(samples[0].Value.Float64())
+			}
+			return nil
+		}),
+	)
+
+	return err
+}
+
+// gcPausesMetric is the distribution of GC stop-the-world pause latencies
+// since the process started.
+const gcPausesMetric = "/gc/pauses:seconds"
+
+// gcPauseBuckets are the bucket boundaries of go.gc.pause in seconds, GC
+// pauses are usually tens of microseconds to a few milliseconds.
+var gcPauseBuckets = []float64{
+	0.00001, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1,
+}
+
+// startGCPauseMetrics reports every GC pause as go.gc.pause. The runtime only
+// keeps a cumulative distribution of pauses, so it's read every interval and
+// the new pauses in each bucket are recorded into the histogram.
+func startGCPauseMetrics(provider metric.MeterProvider, interval time.Duration) (stop func(), err error) {
+	m := provider.Meter(tracerInstrumentationLibName,
+		metric.WithInstrumentationVersion(tracerInstrumentationVersion),
+	)
+
+	histogram, err := m.Float64Histogram("go.gc.pause",
+		metric.WithUnit("s"),
+		metric.WithDescription("Distribution of GC stop-the-world pause latencies."),
+		metric.WithExplicitBucketBoundaries(gcPauseBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last []uint64
+		samples := []runtimemetrics.Sample{{Name: gcPausesMetric}}
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			runtimemetrics.Read(samples)
+			if samples[0].Value.Kind() != runtimemetrics.KindFloat64Histogram {
+				continue
+			}
+			last = recordGCPauses(histogram, samples[0].Value.Float64Histogram(), last)
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// recordGCPauses records pauses which are counted in h but not in last, and
+// returns the counts of h to be compared next time.
+func recordGCPauses(histogram metric.Float64Histogram, h *runtimemetrics.Float64Histogram, last []uint64) []uint64 {
+	ctx := context.Background()
+	for i, count := range h.Counts {
+		if i < len(last) {
+			count -= last[i]
+		}
+		if count == 0 {
+			continue
+		}
+
+		// bucket i is [Buckets[i], Buckets[i+1]), the upper bound is used
+		// unless it's +Inf.
+		value := h.Buckets[i+1]
+		if math.IsInf(value, 1) {
+			value = h.Buckets[i]
+		}
+		for ; count > 0; count-- {
+			histogram.Record(ctx, value)
+		}
+	}
+
+	return append(last[:0], h.Counts...)
+}
+
+// MetricsHandler returns a http.Handler which exposes metrics in prometheus
+// text format. It only works while WithRuntimeMetrics or WithSpanMetrics is
+// enabled and no metric exporter is configured in Setup, otherwise metrics are
+// exported by the configured exporter and 404 would be returned.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, _ := _metricsHandler.Load().(http.HandlerFunc)
+		if h == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
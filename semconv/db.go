@@ -0,0 +1,33 @@
+package semconv
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	conventions "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// DB describes a call to database.
+type DB struct {
+	System    string // System is the database management system, such as: mysql, redis.
+	Name      string // Name is the database name.
+	Statement string // Statement should be sanitized, such as: SELECT * FROM users WHERE id = ?.
+	Operation string // Operation is the name of operation, such as: SELECT, GET.
+	Table     string // Table is the primary table of Statement.
+	User      string
+	PeerName  string
+	PeerPort  int
+}
+
+// Attributes returns the attributes of DB.
+func (d DB) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 8)
+	attrs = appendString(attrs, conventions.DBSystemKey, d.System)
+	attrs = appendString(attrs, conventions.DBNameKey, d.Name)
+	attrs = appendString(attrs, conventions.DBStatementKey, d.Statement)
+	attrs = appendString(attrs, conventions.DBOperationKey, d.Operation)
+	attrs = appendString(attrs, conventions.DBSQLTableKey, d.Table)
+	attrs = appendString(attrs, conventions.DBUserKey, d.User)
+	attrs = appendString(attrs, conventions.NetPeerNameKey, d.PeerName)
+	attrs = appendInt(attrs, conventions.NetPeerPortKey, d.PeerPort)
+
+	return attrs
+}
@@ -0,0 +1,43 @@
+package semconv
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	conventions "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Exception describes an error or panic, it's recorded as attributes of the
+// "exception" event.
+type Exception struct {
+	Type       string // Type is the type of error, such as: *errors.errorString.
+	Message    string
+	Stacktrace string
+	Escaped    bool // Escaped means the exception escapes the scope of span, such as: a panic.
+}
+
+// ExceptionFromError creates an Exception from err, Type is the dynamic type
+// of err.
+func ExceptionFromError(err error) Exception {
+	if err == nil {
+		return Exception{}
+	}
+
+	return Exception{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+	}
+}
+
+// Attributes returns the attributes of Exception.
+func (e Exception) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 4)
+	attrs = appendString(attrs, conventions.ExceptionTypeKey, e.Type)
+	attrs = appendString(attrs, conventions.ExceptionMessageKey, e.Message)
+	attrs = appendString(attrs, conventions.ExceptionStacktraceKey, e.Stacktrace)
+	if e.Escaped {
+		attrs = append(attrs, conventions.ExceptionEscapedKey.Bool(true))
+	}
+
+	return attrs
+}
@@ -0,0 +1,191 @@
+package semconv
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	conventions "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Legacy keys used by the gin middleware and the resty client before.
+const (
+	legacyHTTPStatusSuccessKey = attribute.Key("http.status.success")
+	legacyHTTPStatusCodeKey    = attribute.Key("http.status.code")
+	legacyHTTPPathKey          = attribute.Key("http.path")
+	legacyMethodKey            = attribute.Key("method")
+	legacyURLKey               = attribute.Key("url")
+)
+
+// HTTPServer describes an HTTP request handled by server.
+type HTTPServer struct {
+	Method    string
+	Route     string // Route is the matched route template, such as: /users/:id.
+	Target    string // Target is the path and query of request, such as: /users/1?a=b.
+	Scheme    string
+	HostName  string
+	HostPort  int
+	ClientIP  string
+	UserAgent string
+	Flavor    string // Flavor is the HTTP protocol version, such as: 1.1, 2.0.
+
+	StatusCode            int // StatusCode is 0 if the response has not been written.
+	RequestContentLength  int64
+	ResponseContentLength int64
+}
+
+// HTTPServerFromRequest creates an HTTPServer from r, Route, StatusCode and
+// ResponseContentLength should be filled by caller. ClientIP is the address
+// of peer, caller should override it if the server is behind proxies.
+func HTTPServerFromRequest(r *http.Request) HTTPServer {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	hostName, hostPort := splitHostPort(r.Host)
+	clientIP, _ := splitHostPort(r.RemoteAddr)
+
+	return HTTPServer{
+		Method:               r.Method,
+		Target:               r.RequestURI,
+		Scheme:               scheme,
+		HostName:             hostName,
+		HostPort:             hostPort,
+		ClientIP:             clientIP,
+		UserAgent:            r.UserAgent(),
+		Flavor:               flavor(r.ProtoMajor, r.ProtoMinor),
+		RequestContentLength: r.ContentLength,
+	}
+}
+
+// Attributes returns the attributes of HTTPServer.
+func (h HTTPServer) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 12)
+	attrs = appendString(attrs, conventions.HTTPMethodKey, h.Method)
+	attrs = appendString(attrs, conventions.HTTPRouteKey, h.Route)
+	attrs = appendString(attrs, conventions.HTTPTargetKey, h.Target)
+	attrs = appendString(attrs, conventions.HTTPSchemeKey, h.Scheme)
+	attrs = appendString(attrs, conventions.NetHostNameKey, h.HostName)
+	attrs = appendInt(attrs, conventions.NetHostPortKey, h.HostPort)
+	attrs = appendString(attrs, conventions.HTTPClientIPKey, h.ClientIP)
+	attrs = appendString(attrs, conventions.HTTPUserAgentKey, h.UserAgent)
+	attrs = appendString(attrs, conventions.HTTPFlavorKey, h.Flavor)
+	attrs = appendInt(attrs, conventions.HTTPStatusCodeKey, h.StatusCode)
+	attrs = appendInt64(attrs, conventions.HTTPRequestContentLengthKey, h.RequestContentLength)
+	attrs = appendInt64(attrs, conventions.HTTPResponseContentLengthKey, h.ResponseContentLength)
+
+	return attrs
+}
+
+// LegacyAttributes returns http.status.success, http.status.code and
+// http.path which are recorded by the gin middleware before. http.method is
+// not included since it's the same key in Attributes.
+func (h HTTPServer) LegacyAttributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+	if h.StatusCode != 0 {
+		attrs = append(attrs,
+			legacyHTTPStatusSuccessKey.Bool(h.StatusCode < http.StatusBadRequest),
+			legacyHTTPStatusCodeKey.Int(h.StatusCode),
+		)
+	}
+	path := h.Target
+	if idx := strings.IndexByte(path, '?'); idx >= 0 {
+		path = path[:idx]
+	}
+	attrs = appendString(attrs, legacyHTTPPathKey, path)
+
+	return attrs
+}
+
+// HTTPClient describes an HTTP request sent by client.
+type HTTPClient struct {
+	Method      string
+	URL         string // URL is the full request URL without credentials.
+	PeerName    string
+	PeerPort    int
+	ResendCount int
+
+	StatusCode            int // StatusCode is 0 if no response received.
+	RequestContentLength  int64
+	ResponseContentLength int64
+}
+
+// NewHTTPClient creates an HTTPClient from method and rawURL, the credentials
+// in rawURL are removed, and the peer is parsed from rawURL.
+func NewHTTPClient(method, rawURL string) HTTPClient {
+	h := HTTPClient{
+		Method: method,
+		URL:    rawURL,
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return h
+	}
+	if u.User != nil {
+		u.User = nil
+		h.URL = u.String()
+	}
+	h.PeerName = u.Hostname()
+	h.PeerPort = portOf(u.Port(), u.Scheme)
+
+	return h
+}
+
+// portOf returns the port number, the default port of scheme is used if
+// port is empty.
+func portOf(port, scheme string) int {
+	if port == "" {
+		switch scheme {
+		case "http":
+			return 80
+		case "https":
+			return 443
+		}
+		return 0
+	}
+
+	_, p := splitHostPort(":" + port)
+	return p
+}
+
+// Attributes returns the attributes of HTTPClient.
+func (h HTTPClient) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 8)
+	attrs = appendString(attrs, conventions.HTTPMethodKey, h.Method)
+	attrs = appendString(attrs, conventions.HTTPURLKey, h.URL)
+	attrs = appendString(attrs, conventions.NetPeerNameKey, h.PeerName)
+	attrs = appendInt(attrs, conventions.NetPeerPortKey, h.PeerPort)
+	attrs = appendInt(attrs, conventions.HTTPResendCountKey, h.ResendCount)
+	attrs = appendInt(attrs, conventions.HTTPStatusCodeKey, h.StatusCode)
+	attrs = appendInt64(attrs, conventions.HTTPRequestContentLengthKey, h.RequestContentLength)
+	attrs = appendInt64(attrs, conventions.HTTPResponseContentLengthKey, h.ResponseContentLength)
+
+	return attrs
+}
+
+// LegacyAttributes returns method and url which are recorded by the resty
+// client before.
+func (h HTTPClient) LegacyAttributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2)
+	attrs = appendString(attrs, legacyMethodKey, h.Method)
+	attrs = appendString(attrs, legacyURLKey, h.URL)
+
+	return attrs
+}
+
+func flavor(major, minor int) string {
+	switch {
+	case major == 1 && minor == 0:
+		return "1.0"
+	case major == 1 && minor == 1:
+		return "1.1"
+	case major == 2:
+		return "2.0"
+	case major == 3:
+		return "3.0"
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+package semconv
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	conventions "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Values of Messaging.Operation.
+const (
+	MessagingOperationPublish = "publish"
+	MessagingOperationReceive = "receive"
+	MessagingOperationProcess = "process"
+)
+
+// Values of Messaging.DestinationKind.
+const (
+	MessagingDestinationQueue = "queue"
+	MessagingDestinationTopic = "topic"
+)
+
+// Messaging describes a message published to or consumed from a broker.
+type Messaging struct {
+	System          string // System is the messaging system, such as: kafka, rabbitmq.
+	Destination     string // Destination is the name of queue or topic.
+	DestinationKind string // DestinationKind is one of MessagingDestinationQueue and MessagingDestinationTopic.
+	Operation       string // Operation is one of MessagingOperationPublish, Receive and Process.
+	MessageID       string
+	ConversationID  string
+	PayloadSize     int64 // PayloadSize is the size of message body in bytes.
+}
+
+// Attributes returns the attributes of Messaging.
+func (m Messaging) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 7)
+	attrs = appendString(attrs, conventions.MessagingSystemKey, m.System)
+	attrs = appendString(attrs, conventions.MessagingDestinationNameKey, m.Destination)
+	attrs = appendString(attrs, conventions.MessagingDestinationKindKey, m.DestinationKind)
+	attrs = appendString(attrs, conventions.MessagingOperationKey, m.Operation)
+	attrs = appendString(attrs, conventions.MessagingMessageIDKey, m.MessageID)
+	attrs = appendString(attrs, conventions.MessagingMessageConversationIDKey, m.ConversationID)
+	attrs = appendInt64(attrs, conventions.MessagingMessagePayloadSizeBytesKey, m.PayloadSize)
+
+	return attrs
+}
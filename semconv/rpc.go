@@ -0,0 +1,53 @@
+package semconv
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	conventions "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// RPCSystemGRPC is the value of rpc.system for gRPC.
+const RPCSystemGRPC = "grpc"
+
+// RPC describes a remote procedure call, on both client and server side.
+type RPC struct {
+	System   string // System is the remoting system, such as: grpc.
+	Service  string // Service is the full name of service, such as: helloworld.Greeter.
+	Method   string
+	PeerName string
+	PeerPort int
+}
+
+// RPCFromFullMethod creates an RPC from gRPC style full method, such as:
+// /helloworld.Greeter/SayHello.
+func RPCFromFullMethod(system, fullMethod string) RPC {
+	r := RPC{System: system}
+
+	name := strings.TrimPrefix(fullMethod, "/")
+	if idx := strings.LastIndexByte(name, '/'); idx >= 0 {
+		r.Service, r.Method = name[:idx], name[idx+1:]
+	} else {
+		r.Method = name
+	}
+
+	return r
+}
+
+// Attributes returns the attributes of RPC.
+func (r RPC) Attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 5)
+	attrs = appendString(attrs, conventions.RPCSystemKey, r.System)
+	attrs = appendString(attrs, conventions.RPCServiceKey, r.Service)
+	attrs = appendString(attrs, conventions.RPCMethodKey, r.Method)
+	attrs = appendString(attrs, conventions.NetPeerNameKey, r.PeerName)
+	attrs = appendInt(attrs, conventions.NetPeerPortKey, r.PeerPort)
+
+	return attrs
+}
+
+// GRPCStatusCode returns rpc.grpc.status_code attribute, code is the numeric
+// value of codes.Code.
+func GRPCStatusCode(code uint32) attribute.KeyValue {
+	return conventions.RPCGRPCStatusCodeKey.Int64(int64(code))
+}
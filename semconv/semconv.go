@@ -0,0 +1,54 @@
+// Package semconv provides typed builders of span attributes which follow the
+// OpenTelemetry semantic conventions (v1.17.0), so that collector processors
+// and exporters (such as the sentry exporter) could recognise spans created by
+// contrib packages.
+//
+// Each builder has an Attributes method which returns the attributes of the
+// non-zero fields. Builders which replace keys used by contrib packages before
+// also have a LegacyAttributes method, it returns the legacy keys only, and is
+// emitted by contrib packages in compatibility mode.
+package semconv
+
+import (
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func appendString(attrs []attribute.KeyValue, key attribute.Key, v string) []attribute.KeyValue {
+	if v == "" {
+		return attrs
+	}
+
+	return append(attrs, key.String(v))
+}
+
+func appendInt(attrs []attribute.KeyValue, key attribute.Key, v int) []attribute.KeyValue {
+	if v <= 0 {
+		return attrs
+	}
+
+	return append(attrs, key.Int(v))
+}
+
+func appendInt64(attrs []attribute.KeyValue, key attribute.Key, v int64) []attribute.KeyValue {
+	if v <= 0 {
+		return attrs
+	}
+
+	return append(attrs, key.Int64(v))
+}
+
+// splitHostPort splits hostport into host and port, port is 0 if it's missing
+// or invalid.
+func splitHostPort(hostport string) (host string, port int) {
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		// no port in hostport.
+		return hostport, 0
+	}
+
+	port, _ = strconv.Atoi(portStr)
+	return host, port
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.5.0"
@@ -43,6 +48,35 @@ func newExporter(so setupOption) (exp trace.SpanExporter, err error) {
 	return exp, err
 }
 
+// meterShutdownTimeout bounds flushing metrics while shutting down.
+const meterShutdownTimeout = 3 * time.Second
+
+// newMetricReader returns a reader which exports metrics periodically, nil
+// means no exporter is configured.
+func newMetricReader(so setupOption) (reader sdkmetric.Reader, err error) {
+	switch so.metricExporter {
+	case OTLP:
+		var exp sdkmetric.Exporter
+		exp, err = otlpmetricgrpc.New(context.Background(),
+			otlpmetricgrpc.WithInsecure(),
+			otlpmetricgrpc.WithEndpoint(so.oltpEndpoint),
+		)
+		if err == nil {
+			reader = sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(so.metricInterval))
+		}
+	case NONE:
+		return nil, nil
+	default:
+		err = errors.New("unknown metric exporter")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "newMetricReader failed")
+	}
+
+	return reader, nil
+}
+
 // newResource returns a resource describing this application.
 // DONE(@yeqown): allow modifying and configured by developer by WithXXX API,
 // also try extract from environment variables while some of them are empty.
@@ -78,6 +112,11 @@ func newResource(so setupOption) *resource.Resource {
 // env from environment variable: RUN_ENV, DEPLOY_ENV;
 // namespace from environment variable: NAMESPACE;
 // sampleRate set 0.2, means 20% of traces will be sampled, or you can set OTEL_SAMPLE_RATE=[0..1.0];
+// metrics exporter from environment variable: OTEL_METRICS_EXPORTER=[otlp|none], default is otlp;
+// metrics export interval in milliseconds from environment variable: OTEL_METRIC_EXPORT_INTERVAL;
+// tracing is disabled if environment variable OTEL_SDK_DISABLED=true;
+// propagators from environment variable: OTEL_PROPAGATORS=[tracecontext,b3,b3multi,jaeger,sentry], default is tracecontext,
+// and the sentry propagator reads public key from environment variable SENTRY_DSN;
 func SetupDefault() (shutdown func(), err error) {
 	defaultOrFromEnv := func(_default string, candidateKeys ...string) (value string) {
 		value = _default
@@ -106,7 +145,7 @@ func SetupDefault() (shutdown func(), err error) {
 			"parse %s failed: %v\n", _fraction, err)
 	}
 
-	return Setup(
+	opts := []SetupOption{
 		WithServerName(name),
 		WithServerVersion(version),
 		WithEnv(env),
@@ -115,26 +154,96 @@ func SetupDefault() (shutdown func(), err error) {
 		WithPodIP(podIP),
 		WithOtlpExporter(otelCollectorEndpoint),
 		WithSampleRate(sampleFraction),
-	)
+	}
+
+	if defaultOrFromEnv("otlp", "OTEL_METRICS_EXPORTER") == "none" {
+		opts = append(opts, WithoutMetricExporter())
+	}
+	_interval := defaultOrFromEnv("30000", "OTEL_METRIC_EXPORT_INTERVAL")
+	if interval, err := strconv.Atoi(_interval); err == nil {
+		opts = append(opts, WithMetricExportInterval(time.Duration(interval)*time.Millisecond))
+	} else {
+		fmt.Printf("[med/opentelemetry] WARNNING: OTEL_METRIC_EXPORT_INTERVAL must be an integer, "+
+			"parse %s failed: %v\n", _interval, err)
+	}
+
+	sentryOptions := []SentryOption{
+		WithSentryDSN(os.Getenv("SENTRY_DSN")),
+		WithSentryEnvironment(env),
+		WithSentryRelease(version),
+		WithSentrySampleRate(sampleFraction),
+	}
+	if propagators := propagatorsFromEnv(os.Getenv("OTEL_PROPAGATORS"), sentryOptions...); len(propagators) != 0 {
+		opts = append(opts, WithPropagators(propagators...))
+	}
+
+	return Setup(opts...)
+}
+
+// propagatorsFromEnv parses propagators from comma separated names, such as:
+// tracecontext,b3,jaeger,sentry. Unknown names are ignored, sentryOptions are
+// used by the sentry propagator.
+func propagatorsFromEnv(value string, sentryOptions ...SentryOption) []TraceContextPropagator {
+	if value == "" {
+		return nil
+	}
+
+	propagators := make([]TraceContextPropagator, 0, 4)
+	for _, name := range strings.Split(value, ",") {
+		switch name = strings.TrimSpace(strings.ToLower(name)); name {
+		case "tracecontext":
+			propagators = append(propagators, W3CPropagator())
+		case "b3", "b3multi":
+			propagators = append(propagators, B3Propagator())
+		case "jaeger":
+			propagators = append(propagators, JaegerPropagator())
+		case "sentry":
+			propagators = append(propagators, SentryPropagator(sentryOptions...))
+		default:
+			fmt.Printf("[med/opentelemetry] WARNNING: unknown propagator %s in OTEL_PROPAGATORS\n", name)
+		}
+	}
+
+	return propagators
 }
 
-var _setupOnce sync.Once
+var (
+	_setupMu  sync.Mutex
+	_shutdown func()
+)
 
-// Setup would only execute once if it is called multiple times. Of course,
-// if setup failed, it would return an error and allows the caller to retry.
-// After setup, open telemetry's sdk has been initialized with TracerProvider
-// and Propagator across processes.
+// Setup would only execute once if it is called multiple times, and the
+// shutdown function returned by the first successful call would be returned,
+// until the shutdown function is called. Of course, if setup failed, it would
+// return an error and allows the caller to retry. After setup, open telemetry's
+// sdk has been initialized with TracerProvider, MeterProvider and Propagator
+// across processes.
 func Setup(opts ...SetupOption) (shutdown func(), err error) {
-	_setupOnce.Do(func() {
-		shutdown, err = setup(opts...)
-	})
+	_setupMu.Lock()
+	defer _setupMu.Unlock()
+
+	if _shutdown != nil {
+		return _shutdown, nil
+	}
 
+	shutdownProviders, err := setup(opts...)
 	if err != nil {
-		// re-create once avoid setting up once but failed.
-		_setupOnce = sync.Once{}
+		return nil, err
 	}
 
-	return shutdown, err
+	var once sync.Once
+	_shutdown = func() {
+		once.Do(func() {
+			shutdownProviders()
+
+			// allows to setup again after shutdown.
+			_setupMu.Lock()
+			_shutdown = nil
+			_setupMu.Unlock()
+		})
+	}
+
+	return _shutdown, nil
 }
 
 func setup(opts ...SetupOption) (func(), error) {
@@ -142,6 +251,11 @@ func setup(opts ...SetupOption) (func(), error) {
 	for _, o := range opts {
 		o.apply(&so)
 	}
+	if so.disabled {
+		fmt.Printf("[med/opentelemetry] tracing is disabled\n")
+		setDisabled(true)
+		return func() { setDisabled(false) }, nil
+	}
 	if err := fixSetupOption(&so); err != nil {
 		return nil, errors.Wrap(err, "setup try to fixSetupOption")
 	}
@@ -153,22 +267,87 @@ func setup(opts ...SetupOption) (func(), error) {
 		return nil, errors.Wrap(err, "setup create exporterEnum")
 	}
 
-	provider := trace.NewTracerProvider(
+	reader, err := newMetricReader(so)
+	if err != nil {
+		return nil, errors.Wrap(err, "setup create metric reader")
+	}
+
+	res := newResource(so)
+	meterProviderOptions := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+		// measurements carry the sampled span in context as exemplars.
+		sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter),
+	}
+	var handler http.Handler
+	if reader == nil && (so.runtimeMetricsInterval > 0 || so.spanMetrics != nil) {
+		// no metric exporter, runtime and span metrics are exposed to be scraped.
+		if reader, handler, err = newPrometheusReader(); err != nil {
+			return nil, errors.Wrap(err, "setup create prometheus reader")
+		}
+	}
+	if reader != nil {
+		meterProviderOptions = append(meterProviderOptions, sdkmetric.WithReader(reader))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterProviderOptions...)
+
+	sampler := trace.TraceIDRatioBased(so.sampleRatio)
+	providerOptions := []trace.TracerProviderOption{
 		trace.WithBatcher(exporter),
-		trace.WithResource(newResource(so)),
-		trace.WithSampler(trace.TraceIDRatioBased(so.sampleRatio)),
-	)
-	// generate a shutdown function to close trace provider.
+		trace.WithResource(res),
+		// derives trace ID from request ID, see WithDeterministicTraceID.
+		trace.WithIDGenerator(newIDGenerator()),
+	}
+	if so.spanMetrics != nil {
+		// span metrics processor should see all spans, but the batcher
+		// only exports sampled spans.
+		var processor *spanMetricsProcessor
+		if processor, err = newSpanMetricsProcessor(meterProvider, so.spanMetrics); err != nil {
+			return nil, errors.Wrap(err, "setup create span metrics processor")
+		}
+		providerOptions = append(providerOptions, trace.WithSpanProcessor(processor))
+		sampler = recordAllSampler{Sampler: sampler}
+	}
+	providerOptions = append(providerOptions, trace.WithSampler(sampler))
+	provider := trace.NewTracerProvider(providerOptions...)
+
+	stopRuntimeMetrics := func() {}
+	if so.runtimeMetricsInterval > 0 {
+		if stopRuntimeMetrics, err = startRuntimeMetrics(meterProvider, so.runtimeMetricsInterval); err != nil {
+			return nil, errors.Wrap(err, "setup start runtime metrics")
+		}
+	}
+
+	// generate a shutdown function to close trace provider and meter provider.
 	shutdown := func() {
 		if err = provider.Shutdown(context.Background()); err != nil {
 			log.Fatal(err)
 		}
+		stopRuntimeMetrics()
+		// metrics are less important than traces, failing to flush them
+		// should neither stop nor hold the process for long.
+		ctx, cancel := context.WithTimeout(context.Background(), meterShutdownTimeout)
+		defer cancel()
+		if err = meterProvider.Shutdown(ctx); err != nil {
+			log.Printf("[med/opentelemetry] WARNNING: shutdown meter provider failed: %v\n", err)
+		}
+
+		// MetricsHandler should not serve the reader of a shutdown provider.
+		if handler != nil {
+			_metricsHandler.Store(http.HandlerFunc(nil))
+		}
 	}
 
-	// register tracer provider
+	if handler != nil {
+		_metricsHandler.Store(http.HandlerFunc(handler.ServeHTTP))
+	}
+	// register tracer provider and meter provider
 	otel.SetTracerProvider(provider)
-	// no need to set this, tracing use custom TraceContextPropagator.
-	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetMeterProvider(meterProvider)
+	if len(so.propagators) != 0 {
+		SetPropagator(NewCompositePropagator(so.propagators...))
+	}
+	// other instrumentation uses the same propagator as contrib packages.
+	otel.SetTextMapPropagator(globalPropagator{})
 
 	return shutdown, nil
 }
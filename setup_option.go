@@ -3,12 +3,15 @@ package tracing
 import (
 	"errors"
 	"os"
+	"strings"
+	"time"
 )
 
 type exporterEnum string
 
 const (
 	OTLP exporterEnum = "OTLP"
+	NONE exporterEnum = "NONE"
 	//JAEGER exporterEnum = "JAEGER"
 	//SENTRY exporterEnum = "SENTRY"
 )
@@ -28,6 +31,17 @@ type setupOption struct {
 	oltpEndpoint string // it could not be empty while exporter is OTLP.
 
 	sampleRatio float64 // sampleRatio is the sampling ratio of trace. 1.0 means 100% sampling, 0 means 0% sampling.
+
+	spanMetrics *spanMetricsOption // spanMetrics is not nil means RED metrics should be derived from spans.
+
+	metricExporter exporterEnum  // metricExporter shares the endpoint with exporter, empty (default) means the same as exporter.
+	metricInterval time.Duration // metricInterval is the interval of exporting metrics.
+
+	runtimeMetricsInterval time.Duration // runtimeMetricsInterval > 0 means collecting go runtime metrics.
+
+	disabled bool // disabled means tracing is turned off, StartSpan always returns a noop span.
+
+	propagators []TraceContextPropagator // propagators is empty means using the propagator of GetPropagator.
 }
 
 func defaultSetupOption() setupOption {
@@ -48,15 +62,25 @@ func defaultSetupOption() setupOption {
 		//sentryDSN:       "",
 		// 如果没有指定endpoint，则使用默认的HOST和端口 localhost:4317
 		// DONE(@yeqown): 使用 agent 模式部署 otelcol 后，采用 NodeIP:4317 作为默认值
-		oltpEndpoint: defaultHost + ":4317",
-		sampleRatio:  1.0,
+		oltpEndpoint:   defaultHost + ":4317",
+		sampleRatio:    1.0,
+		spanMetrics:    nil,
+		metricExporter: "",
+		metricInterval: 30 * time.Second,
+
+		runtimeMetricsInterval: 0,
+
+		disabled: strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true"),
+
+		propagators: nil,
 	}
 }
 
 var (
-	ErrUnknownExporter   = errors.New("unknown exporterEnum type")
-	ErrOtlpEndpointEmpty = errors.New("otlp endpoint could not be empty")
-	ErrServerNameEmpty   = errors.New("server name could not be empty")
+	ErrUnknownExporter       = errors.New("unknown exporterEnum type")
+	ErrOtlpEndpointEmpty     = errors.New("otlp endpoint could not be empty")
+	ErrServerNameEmpty       = errors.New("server name could not be empty")
+	ErrUnknownMetricExporter = errors.New("unknown metric exporterEnum type")
 	//ErrJaegerAgentHostEmpty = errors.New("jaeger agent host could not be empty")
 )
 
@@ -79,6 +103,20 @@ func fixSetupOption(so *setupOption) error {
 		return ErrServerNameEmpty
 	}
 
+	if so.metricExporter == "" {
+		// metrics are exported along with traces by default.
+		so.metricExporter = so.exporter
+	}
+	switch so.metricExporter {
+	case OTLP, NONE:
+	default:
+		return ErrUnknownMetricExporter
+	}
+
+	if so.metricInterval <= 0 {
+		so.metricInterval = defaultSetupOption().metricInterval
+	}
+
 	return nil
 }
 
@@ -160,3 +198,78 @@ func WithSampleRate(fraction float64) SetupOption {
 		o.sampleRatio = fraction
 	})
 }
+
+// WithSpanMetrics derives RED (rate, errors, duration) metrics from all ended
+// spans, sampled or not, and records them by the MeterProvider of Setup. They
+// are exported by the metric exporter, or exposed by MetricsHandler if
+// WithoutMetricExporter is set.
+//
+// NOTE: to see unsampled spans, every span which the sampler drops is still
+// recorded (but not exported), so attributes and events are collected for
+// 100% of spans even if WithSampleRate is 0.01. The CPU and memory cost is
+// the same as recording every span.
+func WithSpanMetrics(opts ...SpanMetricsOption) SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.spanMetrics = defaultSpanMetricsOption()
+		for _, opt := range opts {
+			opt.apply(o.spanMetrics)
+		}
+	})
+}
+
+// WithOtlpMetricExporter exports metrics to the same OTLP endpoint as traces.
+// It's the default while traces are exported by OTLP.
+func WithOtlpMetricExporter() SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.metricExporter = OTLP
+	})
+}
+
+// WithoutMetricExporter disables exporting metrics, the MeterProvider is
+// still created, but measurements would not be exported. Metrics are exported
+// by the same exporter as traces by default.
+func WithoutMetricExporter() SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.metricExporter = NONE
+	})
+}
+
+// WithMetricExportInterval sets the interval of exporting metrics, default is 30s.
+func WithMetricExportInterval(interval time.Duration) SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.metricInterval = interval
+	})
+}
+
+// WithRuntimeMetrics collects go runtime (goroutines, GC pause, heap) and
+// process CPU stats every interval, tagged with the same resource attributes
+// as traces. They are exported by the metric exporter, or exposed by
+// MetricsHandler if WithoutMetricExporter is set.
+func WithRuntimeMetrics(interval time.Duration) SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		if interval <= 0 {
+			interval = 15 * time.Second
+		}
+		o.runtimeMetricsInterval = interval
+	})
+}
+
+// WithDisabled turns off tracing, no exporter and provider would be created,
+// StartSpan and SpanFromContext always return a noop span without allocation.
+// It's the same as setting environment variable OTEL_SDK_DISABLED=true.
+func WithDisabled() SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.disabled = true
+	})
+}
+
+// WithPropagators sets the propagators of trace context, such as:
+// W3CPropagator, B3Propagator, JaegerPropagator and SentryPropagator. The
+// trace context is extracted from the first format present in carrier, and
+// injected into all formats. All contrib packages and other OpenTelemetry
+// instrumentation use them after Setup.
+func WithPropagators(propagators ...TraceContextPropagator) SetupOption {
+	return fnSetupOption(func(o *setupOption) {
+		o.propagators = propagators
+	})
+}
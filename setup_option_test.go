@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fixSetupOption_MetricExporter(t *testing.T) {
+	// metrics are exported along with traces by default.
+	so := defaultSetupOption()
+	require.NoError(t, fixSetupOption(&so))
+	assert.Equal(t, OTLP, so.metricExporter)
+
+	reader, err := newMetricReader(so)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+	_ = reader.Shutdown(context.Background())
+
+	// WithoutMetricExporter opts out.
+	so = defaultSetupOption()
+	WithoutMetricExporter().apply(&so)
+	require.NoError(t, fixSetupOption(&so))
+	assert.Equal(t, NONE, so.metricExporter)
+
+	reader, err = newMetricReader(so)
+	require.NoError(t, err)
+	assert.Nil(t, reader)
+
+	so = defaultSetupOption()
+	so.metricExporter = "UNKNOWN"
+	assert.Equal(t, ErrUnknownMetricExporter, fixSetupOption(&so))
+}
@@ -1,7 +1,17 @@
 package tracing_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
 	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func ExampleSetupDefault() {
@@ -21,3 +31,55 @@ func ExampleSetupDefault() {
 	)
 	_, _ = shutdown, err
 }
+
+func ExampleWithRuntimeMetrics() {
+	shutdown, err := tracing.Setup(
+		tracing.WithServerName("runtime_metrics"),
+		tracing.WithRuntimeMetrics(15*time.Second),
+		// without metric exporter, metrics are exposed by MetricsHandler.
+		tracing.WithoutMetricExporter(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdown()
+
+	http.Handle("/metrics", tracing.MetricsHandler())
+}
+
+func Test_RuntimeMetrics(t *testing.T) {
+	shutdown, err := tracing.Setup(
+		tracing.WithServerName("runtime_metrics"),
+		tracing.WithNamespace("test"),
+		tracing.WithRuntimeMetrics(10*time.Millisecond),
+		tracing.WithoutMetricExporter(),
+	)
+	require.NoError(t, err)
+
+	scrape := func() (int, string) {
+		w := httptest.NewRecorder()
+		tracing.MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Code, w.Body.String()
+	}
+
+	// GC pauses are recorded every interval.
+	runtime.GC()
+	var body string
+	require.Eventually(t, func() bool {
+		_, body = scrape()
+		return strings.Contains(body, "go_gc_pause_seconds_count")
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Contains(t, body, "go_gc_pause_seconds_bucket")
+	assert.Contains(t, body, "process_runtime_go_goroutines")
+	assert.Contains(t, body, "process_runtime_go_mem_heap_alloc_bytes")
+	assert.Contains(t, body, "process_runtime_go_mem_heap_inuse_bytes")
+	assert.Contains(t, body, "process_cpu_time")
+	// resource attributes are the same as traces.
+	assert.Contains(t, body, `service_name="runtime_metrics"`)
+
+	// MetricsHandler should not serve the reader of a shutdown provider.
+	shutdown()
+	code, _ := scrape()
+	assert.Equal(t, http.StatusNotFound, code)
+}
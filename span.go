@@ -1,8 +1,6 @@
 package tracing
 
 import (
-	"time"
-
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -66,30 +64,41 @@ type Span interface {
 
 	// End ends the span. same to Finish
 	End()
+
+	// EndWithError ends the span, it records *err and sets the status of span
+	// to Error if *err is not nil. Otherwise the status is left as it is, so
+	// that an Error status set before is kept. It is designed to be used with
+	// defer:
+	//
+	// 	func do(ctx context.Context) (err error) {
+	// 		ctx, sp := tracing.StartSpan(ctx, "do")
+	// 		defer sp.EndWithError(&err)
+	// 		...
+	// 	}
+	EndWithError(err *error)
 }
 
-func wrapSpan(sp trace.Span, psc *trace.SpanContext) Span {
-	if psc == nil {
-		return spanAgent{
-			root: sp,
-			psc:  trace.SpanContext{},
-		}
-	}
+// spanBridge is the trace.Span stored in context. It embeds the span created
+// by otel, so otel and its instrumentations treat it as the parent, and keeps
+// the parent span context to fill TraceContext.ParentSpanID.
+type spanBridge struct {
+	trace.Span
+	psc trace.SpanContext
+}
 
-	return spanAgent{root: sp, psc: *psc}
+func newSpanBridge(sp trace.Span, psc trace.SpanContext) *spanBridge {
+	return &spanBridge{Span: sp, psc: psc}
 }
 
-// spanAgent is the interface implemented by the agent that is
-// responsible for propagating spans. at the same time, it contains
-//
+// spanAgent implements Span by wrapping a spanBridge. It only holds a pointer,
+// so that converting it into Span does not allocate.
 type spanAgent struct {
-	root trace.Span
-	psc  trace.SpanContext
+	root *spanBridge
 }
 
 func (s spanAgent) SpanContext() *TraceContext {
 	sc := s.root.SpanContext()
-	return traceSpanContextToTraceContext(sc, s.psc)
+	return traceSpanContextToTraceContext(sc, s.root.psc)
 }
 func (s spanAgent) RecordError(err error, opts ...SpanEventOption) {
 	o := defaultSpanEventOption()
@@ -108,8 +117,16 @@ func (s spanAgent) LogFields(event string, attrs ...attribute.KeyValue) {
 	s.root.AddEvent(event, trace.WithAttributes(attrs...))
 }
 func (s spanAgent) SetStatus(code Code, message string) { s.root.SetStatus(code, message) }
-func (s spanAgent) Finish()                             { s.root.End(trace.WithTimestamp(time.Now())) }
-func (s spanAgent) End()                                { s.root.End(trace.WithTimestamp(time.Now())) }
+func (s spanAgent) Finish()                             { s.root.End() }
+func (s spanAgent) End()                                { s.root.End() }
+func (s spanAgent) EndWithError(err *error) {
+	if err != nil && *err != nil {
+		s.RecordError(*err)
+		s.SetStatus(Error, (*err).Error())
+	}
+
+	s.End()
+}
 
 func traceSpanContextToTraceContext(sc, psc trace.SpanContext) *TraceContext {
 	return &TraceContext{
@@ -119,5 +136,7 @@ func traceSpanContextToTraceContext(sc, psc trace.SpanContext) *TraceContext {
 		sampled:      sc.IsSampled(),
 		isValid:      sc.IsValid(),
 		isRemote:     sc.IsRemote(),
+		traceFlags:   sc.TraceFlags(),
+		traceState:   sc.TraceState().String(),
 	}
 }
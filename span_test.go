@@ -23,6 +23,8 @@ func (s *spanTestSuite) TearDownSuite() {
 }
 
 func (s *spanTestSuite) SetupSuite() {
+	// no collector while testing, do not wait for exporting.
+	s.T().Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "100")
 	shutdown, err := tracing.SetupDefault()
 	if err != nil {
 		panic(err)
@@ -31,7 +33,7 @@ func (s *spanTestSuite) SetupSuite() {
 	s.shutdown = shutdown
 }
 
-func (s spanTestSuite) Test_spanAgent() {
+func (s *spanTestSuite) Test_spanAgent() {
 	t := s.T()
 
 	ctx, sp := tracing.StartSpan(context.Background(), "test", tracing.WithSpanKind(tracing.SpanKindClient))
@@ -54,7 +56,7 @@ func (s spanTestSuite) Test_spanAgent() {
 	assert.NotEqual(t, tc1.SpanID, tc2.SpanID)
 }
 
-func (s spanTestSuite) Test_spanAgent_recording() {
+func (s *spanTestSuite) Test_spanAgent_recording() {
 	t := s.T()
 
 	ctx, sp := tracing.StartSpan(context.Background(), "test", tracing.WithSpanKind(tracing.SpanKindClient))
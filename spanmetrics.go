@@ -0,0 +1,225 @@
+package tracing
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var defaultSpanMetricsBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+type SpanMetricsOption interface {
+	apply(o *spanMetricsOption)
+}
+
+type spanMetricsOption struct {
+	namespace  string    // namespace is the prefix of metric names.
+	attributes []string  // attributes are the span attribute keys to be used as metric attributes.
+	buckets    []float64 // buckets are the upper bounds of duration histogram in seconds.
+	maxSeries  int       // maxSeries is the cardinality limit of label sets.
+}
+
+func defaultSpanMetricsOption() *spanMetricsOption {
+	return &spanMetricsOption{
+		namespace:  "",
+		attributes: nil,
+		buckets:    defaultSpanMetricsBuckets,
+		maxSeries:  2000,
+	}
+}
+
+type fnSpanMetricsOption func(opts *spanMetricsOption)
+
+func (fn fnSpanMetricsOption) apply(opts *spanMetricsOption) { fn(opts) }
+func newFnSpanMetricsOption(fn func(option *spanMetricsOption)) SpanMetricsOption {
+	return fnSpanMetricsOption(fn)
+}
+
+// WithSpanMetricsNamespace sets the prefix of metric names, such as:
+// "myapp" results in "myapp.span.requests", which is exposed as
+// "myapp_span_requests_total" by MetricsHandler.
+func WithSpanMetricsNamespace(namespace string) SpanMetricsOption {
+	return newFnSpanMetricsOption(func(option *spanMetricsOption) {
+		option.namespace = namespace
+	})
+}
+
+// WithSpanMetricsAttributes specifies span attribute keys which are recorded as
+// metric attributes besides span name, kind and status, such as: "http.method",
+// which is exposed as label "http_method" by MetricsHandler.
+func WithSpanMetricsAttributes(keys ...string) SpanMetricsOption {
+	return newFnSpanMetricsOption(func(option *spanMetricsOption) {
+		option.attributes = append(option.attributes, keys...)
+	})
+}
+
+// WithSpanMetricsBuckets sets the upper bounds of duration histogram in seconds.
+func WithSpanMetricsBuckets(buckets ...float64) SpanMetricsOption {
+	return newFnSpanMetricsOption(func(option *spanMetricsOption) {
+		if len(buckets) == 0 {
+			return
+		}
+
+		option.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(option.buckets)
+	})
+}
+
+// WithSpanMetricsCardinalityLimit limits the count of attribute sets, spans with
+// new attribute sets beyond the limit are aggregated into one overflow series
+// with attribute otel.metric.overflow=true.
+func WithSpanMetricsCardinalityLimit(n int) SpanMetricsOption {
+	return newFnSpanMetricsOption(func(option *spanMetricsOption) {
+		if n > 0 {
+			option.maxSeries = n
+		}
+	})
+}
+
+var _ sdktrace.SpanProcessor = (*spanMetricsProcessor)(nil)
+
+// overflowAttributes is the attribute set of spans beyond the cardinality limit.
+var overflowAttributes = metric.WithAttributes(attribute.Bool("otel.metric.overflow", true))
+
+// spanMetricsProcessor derives RED (rate, errors, duration) metrics from all
+// ended spans, and records them by the instruments of a MeterProvider.
+type spanMetricsProcessor struct {
+	opt *spanMetricsOption
+
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+
+	mu     sync.Mutex
+	series map[attribute.Distinct]struct{} // series are the attribute sets within the cardinality limit.
+}
+
+func newSpanMetricsProcessor(provider metric.MeterProvider, opt *spanMetricsOption) (*spanMetricsProcessor, error) {
+	m := provider.Meter(tracerInstrumentationLibName,
+		metric.WithInstrumentationVersion(tracerInstrumentationVersion),
+	)
+
+	prefix := "span."
+	if opt.namespace != "" {
+		prefix = opt.namespace + ".span."
+	}
+
+	p := &spanMetricsProcessor{
+		opt:    opt,
+		series: make(map[attribute.Distinct]struct{}, 64),
+	}
+
+	var err error
+	if p.requests, err = m.Int64Counter(prefix+"requests",
+		metric.WithUnit("{span}"),
+		metric.WithDescription("The count of ended spans."),
+	); err != nil {
+		return nil, errors.Wrap(err, "newSpanMetricsProcessor create requests counter")
+	}
+	if p.errors, err = m.Int64Counter(prefix+"errors",
+		metric.WithUnit("{span}"),
+		metric.WithDescription("The count of ended spans with error status."),
+	); err != nil {
+		return nil, errors.Wrap(err, "newSpanMetricsProcessor create errors counter")
+	}
+	if p.duration, err = m.Float64Histogram(prefix+"duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("The duration of ended spans."),
+		metric.WithExplicitBucketBoundaries(opt.buckets...),
+	); err != nil {
+		return nil, errors.Wrap(err, "newSpanMetricsProcessor create duration histogram")
+	}
+
+	return p, nil
+}
+
+func (p *spanMetricsProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+func (p *spanMetricsProcessor) Shutdown(ctx context.Context) error                       { return nil }
+func (p *spanMetricsProcessor) ForceFlush(ctx context.Context) error                     { return nil }
+
+func (p *spanMetricsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	kvs := make([]attribute.KeyValue, 0, 3+len(p.opt.attributes))
+	kvs = append(kvs,
+		attribute.String("span_name", s.Name()),
+		attribute.String("span_kind", s.SpanKind().String()),
+		attribute.String("status_code", s.Status().Code.String()),
+	)
+	if len(p.opt.attributes) != 0 {
+		kvs = appendAttributeValues(kvs, p.opt.attributes, s.Attributes())
+	}
+	opt := p.attributesOption(attribute.NewSet(kvs...))
+
+	// sampled spans are attached to the duration as exemplars.
+	ctx := trace.ContextWithSpanContext(context.Background(), s.SpanContext())
+	p.requests.Add(ctx, 1, opt)
+	if s.Status().Code == codes.Error {
+		p.errors.Add(ctx, 1, opt)
+	}
+	p.duration.Record(ctx, s.EndTime().Sub(s.StartTime()).Seconds(), opt)
+}
+
+// attributesOption returns the option of set, or the overflow attributes if
+// set is new and the cardinality limit is exceeded.
+func (p *spanMetricsProcessor) attributesOption(set attribute.Set) metric.MeasurementOption {
+	key := set.Equivalent()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.series[key]; !ok {
+		if len(p.series) >= p.opt.maxSeries {
+			return overflowAttributes
+		}
+		p.series[key] = struct{}{}
+	}
+
+	return metric.WithAttributeSet(set)
+}
+
+// appendAttributeValues appends the value of keys in attrs, an absent key
+// results in an empty string value.
+func appendAttributeValues(dst []attribute.KeyValue, keys []string, attrs []attribute.KeyValue) []attribute.KeyValue {
+	for _, key := range keys {
+		value := ""
+		for _, attr := range attrs {
+			if string(attr.Key) == key {
+				value = attr.Value.Emit()
+				break
+			}
+		}
+		dst = append(dst, attribute.String(key, value))
+	}
+
+	return dst
+}
+
+var _ sdktrace.Sampler = recordAllSampler{}
+
+// recordAllSampler records spans which are dropped by the wrapped Sampler,
+// so that span processors could see all spans, but only sampled spans would
+// be exported.
+type recordAllSampler struct {
+	sdktrace.Sampler
+}
+
+func (s recordAllSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+
+	return result
+}
+
+func (s recordAllSampler) Description() string {
+	return "RecordAll{" + s.Sampler.Description() + "}"
+}
@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_SpanMetricsOption(t *testing.T) {
+	o := defaultSpanMetricsOption()
+	assert.Equal(t, defaultSpanMetricsBuckets, o.buckets)
+	assert.Equal(t, 2000, o.maxSeries)
+
+	WithSpanMetricsNamespace("app").apply(o)
+	WithSpanMetricsAttributes("http.method").apply(o)
+	WithSpanMetricsBuckets(1, 0.1).apply(o)
+	WithSpanMetricsCardinalityLimit(10).apply(o)
+	assert.Equal(t, "app", o.namespace)
+	assert.Equal(t, []string{"http.method"}, o.attributes)
+	assert.Equal(t, []float64{0.1, 1}, o.buckets)
+	assert.Equal(t, 10, o.maxSeries)
+}
+
+func Test_spanMetricsProcessor(t *testing.T) {
+	o := defaultSpanMetricsOption()
+	WithSpanMetricsAttributes("http.method").apply(o)
+	WithSpanMetricsBuckets(0.1, 1).apply(o)
+	WithSpanMetricsCardinalityLimit(2).apply(o)
+
+	reader := sdkmetric.NewManualReader()
+	p, err := newSpanMetricsProcessor(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), o)
+	require.NoError(t, err)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(p),
+		// spans are not sampled, but still be seen by the processor.
+		sdktrace.WithSampler(recordAllSampler{Sampler: sdktrace.NeverSample()}),
+	)
+	tracer := provider.Tracer("test")
+
+	_, sp := tracer.Start(context.Background(), "GET /users",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("http.method", "GET")),
+	)
+	assert.False(t, sp.SpanContext().IsSampled())
+	sp.End()
+
+	_, sp = tracer.Start(context.Background(), "GET /users",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("http.method", "GET")),
+	)
+	sp.SetStatus(codes.Error, "failed")
+	sp.End()
+
+	// exceeds the cardinality limit.
+	for _, name := range []string{"a", "b", "c"} {
+		_, sp = tracer.Start(context.Background(), name)
+		sp.End()
+	}
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	metrics := make(map[string]metricdata.Aggregation, 3)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	ok := attribute.NewSet(
+		attribute.String("span_name", "GET /users"),
+		attribute.String("span_kind", "server"),
+		attribute.String("status_code", "Unset"),
+		attribute.String("http.method", "GET"),
+	)
+	failed := attribute.NewSet(
+		attribute.String("span_name", "GET /users"),
+		attribute.String("span_kind", "server"),
+		attribute.String("status_code", "Error"),
+		attribute.String("http.method", "GET"),
+	)
+	overflow := attribute.NewSet(attribute.Bool("otel.metric.overflow", true))
+
+	requests := sumValues(t, metrics["span.requests"])
+	assert.Equal(t, map[attribute.Distinct]int64{
+		ok.Equivalent(): 1, failed.Equivalent(): 1, overflow.Equivalent(): 3,
+	}, requests)
+	errs := sumValues(t, metrics["span.errors"])
+	assert.Equal(t, map[attribute.Distinct]int64{failed.Equivalent(): 1}, errs)
+
+	duration, isHistogram := metrics["span.duration"].(metricdata.Histogram[float64])
+	require.True(t, isHistogram)
+	require.Len(t, duration.DataPoints, 3)
+	for _, dp := range duration.DataPoints {
+		assert.Equal(t, []float64{0.1, 1}, dp.Bounds)
+		if dp.Attributes.Equivalent() == overflow.Equivalent() {
+			assert.Equal(t, uint64(3), dp.Count)
+			assert.Equal(t, []uint64{3, 0, 0}, dp.BucketCounts)
+		}
+	}
+}
+
+// sumValues returns the values of data points of a sum, keyed by attributes.
+func sumValues(t *testing.T, data metricdata.Aggregation) map[attribute.Distinct]int64 {
+	sum, ok := data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	values := make(map[attribute.Distinct]int64, len(sum.DataPoints))
+	for _, dp := range sum.DataPoints {
+		values[dp.Attributes.Equivalent()] = dp.Value
+	}
+
+	return values
+}
+
+func Test_SpanMetrics_MetricsHandler(t *testing.T) {
+	// no collector while testing, do not wait for exporting.
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "100")
+	shutdown, err := Setup(
+		WithServerName("span_metrics"),
+		WithSpanMetrics(),
+		WithSampleRate(0),
+		WithoutMetricExporter(),
+	)
+	require.NoError(t, err)
+
+	_, sp := StartSpan(context.Background(), "GET /users")
+	sp.End()
+
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `span_name="GET /users"`)
+
+	shutdown()
+	w = httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrInvalidTraceContext = errors.New("invalid trace context")
+)
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// String returns the TraceContext in W3C traceparent form, such as:
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01. The tracestate
+// is not included, use TraceState to get it. An invalid TraceContext
+// results in an empty string.
+func (tc *TraceContext) String() string {
+	if !tc.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", tc.TraceID, tc.SpanID, tc.traceFlags)
+}
+
+// ParseTraceContext parses traceparent and tracestate which are generated by
+// TraceContext.String and TraceContext.TraceState, or received from any
+// W3C trace context compatible carrier. tracestate could be empty.
+//
+// The returned TraceContext is always remote, since it comes from outside of
+// current process.
+func ParseTraceContext(traceparent, tracestate string) (*TraceContext, error) {
+	carrier := mapCarrier{traceparentHeader: traceparent}
+	if tracestate != "" {
+		carrier[tracestateHeader] = tracestate
+	}
+
+	sc := trace.SpanContextFromContext(
+		propagation.TraceContext{}.Extract(context.Background(), carrierAdapter{carrier}),
+	)
+	if !sc.IsValid() {
+		return nil, ErrInvalidTraceContext
+	}
+
+	return traceSpanContextToTraceContext(sc, trace.SpanContext{}), nil
+}
+
+// traceContextJSON is the JSON representation of TraceContext.
+type traceContextJSON struct {
+	TraceParent  string `json:"traceparent"`
+	TraceState   string `json:"tracestate,omitempty"`
+	ParentSpanID string `json:"parent_span_id,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. TraceContext would be marshaled as:
+// {"traceparent":"00-...-01","tracestate":"k=v","parent_span_id":"..."}.
+// It has a value receiver, so that a TraceContext embedded by value is
+// marshaled in the same form as a pointer one.
+func (tc TraceContext) MarshalJSON() ([]byte, error) {
+	if !tc.IsValid() {
+		return []byte("null"), nil
+	}
+
+	v := traceContextJSON{
+		TraceParent: tc.String(),
+		TraceState:  tc.traceState,
+	}
+	if tc.ParentSpanID != (trace.SpanID{}).String() {
+		v.ParentSpanID = tc.ParentSpanID
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null leaves tc untouched.
+func (tc *TraceContext) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	v := traceContextJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	parsed, err := ParseTraceContext(v.TraceParent, v.TraceState)
+	if err != nil {
+		return err
+	}
+	if v.ParentSpanID != "" {
+		parsed.ParentSpanID = v.ParentSpanID
+	}
+
+	*tc = *parsed
+	return nil
+}
+
+// spanContext converts TraceContext back to trace.SpanContext.
+func (tc *TraceContext) spanContext() trace.SpanContext {
+	if !tc.IsValid() {
+		return trace.SpanContext{}
+	}
+
+	traceID, _ := trace.TraceIDFromHex(tc.TraceID)
+	spanID, _ := trace.SpanIDFromHex(tc.SpanID)
+	traceState, _ := trace.ParseTraceState(tc.traceState)
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: tc.traceFlags,
+		TraceState: traceState,
+		Remote:     true,
+	})
+}
+
+// ContextWithTraceContext returns a copy of ctx with tc set as the remote
+// parent, so that StartSpan with the returned context continues the trace
+// described by tc. It is useful to resume a trace after it has been stored
+// in DB or a delay queue. ctx would be returned directly if tc is invalid.
+//
+// NOTE: StartSpan returns a noop span if Setup is never called, so that the
+// span started with the returned context does not keep the trace ID of tc
+// before Setup, TraceContextFromContext of the returned context still does.
+func ContextWithTraceContext(ctx context.Context, tc *TraceContext) context.Context {
+	if !tc.IsValid() {
+		return ctx
+	}
+
+	remote := trace.SpanFromContext(trace.ContextWithRemoteSpanContext(ctx, tc.spanContext()))
+	return trace.ContextWithSpan(ctx, newSpanBridge(remote, trace.SpanContext{}))
+}
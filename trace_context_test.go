@@ -0,0 +1,144 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func ExampleContextWithTraceContext() {
+	ctx, sp := tracing.StartSpan(context.Background(), "produce")
+	defer sp.End()
+
+	// persist trace context into DB or delay queue.
+	stored, _ := json.Marshal(tracing.TraceContextFromContext(ctx))
+
+	// hours later, resume the trace from the stored trace context.
+	tc := new(tracing.TraceContext)
+	if err := json.Unmarshal(stored, tc); err != nil {
+		return
+	}
+	ctx2, sp2 := tracing.StartSpan(tracing.ContextWithTraceContext(context.Background(), tc), "consume")
+	defer sp2.End()
+
+	_ = ctx2
+}
+
+func Test_ParseTraceContext(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tracestate := "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7"
+
+	tc, err := tracing.ParseTraceContext(traceparent, tracestate)
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", tc.SpanID)
+	assert.True(t, tc.IsValid())
+	assert.True(t, tc.IsRemote())
+	assert.True(t, tc.Sampled())
+	assert.Equal(t, traceparent, tc.String())
+	assert.Equal(t, tracestate, tc.TraceState())
+
+	tc1, err := tracing.ParseTraceContext(tc.String(), tc.TraceState())
+	require.NoError(t, err)
+	assert.Equal(t, tc, tc1)
+
+	tc2, err := tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", "")
+	require.NoError(t, err)
+	assert.False(t, tc2.Sampled())
+	assert.Empty(t, tc2.TraceState())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", tc2.String())
+
+	_, err = tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736", "")
+	assert.Equal(t, tracing.ErrInvalidTraceContext, err)
+	_, err = tracing.ParseTraceContext("", "")
+	assert.Equal(t, tracing.ErrInvalidTraceContext, err)
+}
+
+func Test_TraceContext_JSON(t *testing.T) {
+	tc, err := tracing.ParseTraceContext(
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "rojo=00f067aa0ba902b7")
+	require.NoError(t, err)
+	tc.ParentSpanID = "b7ad6b7169203331"
+
+	data, err := json.Marshal(tc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"tracestate": "rojo=00f067aa0ba902b7",
+		"parent_span_id": "b7ad6b7169203331"
+	}`, string(data))
+
+	tc2 := new(tracing.TraceContext)
+	require.NoError(t, json.Unmarshal(data, tc2))
+	assert.Equal(t, tc, tc2)
+
+	data, err = json.Marshal(&tracing.TraceContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"traceparent":"invalid"}`), tc2))
+}
+
+func Test_TraceContext_JSON_ByValue(t *testing.T) {
+	tc, err := tracing.ParseTraceContext(
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "rojo=00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	type job struct {
+		Name  string               `json:"name"`
+		Trace tracing.TraceContext `json:"trace"`
+	}
+
+	data, err := json.Marshal(job{Name: "resume", Trace: *tc})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "resume",
+		"trace": {
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"tracestate": "rojo=00f067aa0ba902b7"
+		}
+	}`, string(data))
+
+	j := job{}
+	require.NoError(t, json.Unmarshal(data, &j))
+	assert.Equal(t, *tc, j.Trace)
+
+	// an empty TraceContext is marshaled as null, and null is unmarshaled back
+	// into an empty TraceContext.
+	data, err = json.Marshal(job{Name: "empty"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"empty","trace":null}`, string(data))
+
+	j = job{}
+	require.NoError(t, json.Unmarshal(data, &j))
+	assert.False(t, j.Trace.IsValid())
+}
+
+func Test_ContextWithTraceContext(t *testing.T) {
+	// StartSpan returns a noop span before Setup, install a sdk provider so
+	// that the resumed span is really started.
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previous)
+
+	tc, err := tracing.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	require.NoError(t, err)
+
+	ctx := tracing.ContextWithTraceContext(context.Background(), tc)
+	assert.Equal(t, tc, tracing.TraceContextFromContext(ctx))
+
+	_, sp := tracing.StartSpan(ctx, "resume")
+	defer sp.End()
+	assert.Equal(t, tc.TraceID, sp.SpanContext().TraceID)
+
+	// invalid trace context takes no effect.
+	ctx2 := tracing.ContextWithTraceContext(context.Background(), &tracing.TraceContext{})
+	assert.False(t, tracing.TraceContextFromContext(ctx2).IsValid())
+}
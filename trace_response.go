@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceResponseHeader is the header of W3C Trace Context Level 2, it
+	// carries trace ID, span ID of server span and sampled flag, such as:
+	// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+	TraceResponseHeader = "traceresponse"
+
+	// LegacyTraceIDHeader is the proprietary header which carries only trace
+	// ID, it's written by gin middleware before traceresponse.
+	LegacyTraceIDHeader = "x-tracing-id"
+)
+
+type TraceResponseOption interface {
+	apply(o *traceResponseOption)
+}
+
+type traceResponseOption struct {
+	traceResponseHeader string
+	traceIDHeader       string
+}
+
+type fnTraceResponseOption func(o *traceResponseOption)
+
+func (fn fnTraceResponseOption) apply(o *traceResponseOption) { fn(o) }
+func newFnTraceResponseOption(fn func(o *traceResponseOption)) TraceResponseOption {
+	return fnTraceResponseOption(fn)
+}
+
+// WithTraceResponseHeader sets the header name of traceresponse, empty
+// header disables it. Default: traceresponse.
+func WithTraceResponseHeader(header string) TraceResponseOption {
+	return newFnTraceResponseOption(func(o *traceResponseOption) {
+		o.traceResponseHeader = header
+	})
+}
+
+// WithTraceIDHeader sets the header name which carries only trace ID, such
+// as: LegacyTraceIDHeader, empty header disables it. Default: disabled.
+func WithTraceIDHeader(header string) TraceResponseOption {
+	return newFnTraceResponseOption(func(o *traceResponseOption) {
+		o.traceIDHeader = header
+	})
+}
+
+func newTraceResponseOption(opts ...TraceResponseOption) traceResponseOption {
+	o := traceResponseOption{
+		traceResponseHeader: TraceResponseHeader,
+		traceIDHeader:       "",
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
+
+// InjectTraceResponse writes traceresponse of the span in ctx into carrier
+// of response, such as: http.Header or grpc trailer metadata. Nothing is
+// written if there is no valid span in ctx.
+func InjectTraceResponse(ctx context.Context, carrier TraceContextCarrier, opts ...TraceResponseOption) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	o := newTraceResponseOption(opts...)
+	if o.traceResponseHeader != "" {
+		carrier.Set(o.traceResponseHeader, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-"+sc.TraceFlags().String())
+	}
+	if o.traceIDHeader != "" {
+		carrier.Set(o.traceIDHeader, sc.TraceID().String())
+	}
+}
+
+// TraceResponseHandler wraps h to write traceresponse into the header of
+// response. The server span should have been started before h, such as:
+//
+//	otelhttp.NewHandler(tracing.TraceResponseHandler(mux), "server")
+func TraceResponseHandler(h http.Handler, opts ...TraceResponseOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		InjectTraceResponse(r.Context(), HeaderCarrier(w.Header()), opts...)
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,45 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tracing "github.com/yeqown/opentelemetry-quake"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InjectTraceResponse(t *testing.T) {
+	h := http.Header{}
+	tracing.InjectTraceResponse(contextWithTestSpan(), h)
+	assert.Equal(t, "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01", h.Get("traceresponse"))
+	assert.Empty(t, h.Get(tracing.LegacyTraceIDHeader))
+
+	h = http.Header{}
+	tracing.InjectTraceResponse(contextWithTestSpan(), h,
+		tracing.WithTraceResponseHeader(""),
+		tracing.WithTraceIDHeader(tracing.LegacyTraceIDHeader),
+	)
+	assert.Empty(t, h.Get("traceresponse"))
+	assert.Equal(t, "994f3d6cebfc1b8f19c52a8c687ab5f3", h.Get(tracing.LegacyTraceIDHeader))
+
+	// nothing is written without span.
+	h = http.Header{}
+	tracing.InjectTraceResponse(context.Background(), h)
+	assert.Empty(t, h)
+}
+
+func Test_TraceResponseHandler(t *testing.T) {
+	handler := tracing.TraceResponseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(contextWithTestSpan())
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "00-994f3d6cebfc1b8f19c52a8c687ab5f3-0c77d121fee1c02a-01", w.Header().Get("traceresponse"))
+}